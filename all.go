@@ -0,0 +1,91 @@
+package di
+
+import (
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// GetAll returns all the components assignable to T with any name in order
+// they were initialized. Not singleton components returned after singletons
+// in order corresponding di.Setup were called. Scoped components returned
+// only if container is scope. Parent components not shadowed within container
+// returned before container components. Components being initialized skipped
+func GetAll[T any](c *Container) ([]T, error) {
+	if err := c.checkGet(); err != nil {
+		return nil, err
+	}
+
+	var (
+		t     T
+		type_ = reflect.TypeOf(&t).Elem()
+		ts    []T
+	)
+
+	for _, owner := range c.chain() {
+		var (
+			comps []*component
+			vals  = make(map[*component]any)
+		)
+
+		// components not initialized yet initialized on request
+		for _, coord := range owner.initOrder {
+			comp := owner.components[coord]
+			if _, found, _ := c.lookup(coord); found != comp || !coord.type_.AssignableTo(type_) || !c.visible(owner, comp) {
+				continue
+			}
+
+			// component requesting all the components it's assignable to
+			// within it's init function is not included
+			if slices.Contains(c.path, comp.coord) {
+				continue
+			}
+
+			val, err := c.resolveAt(owner, comp)
+			if err != nil {
+				return nil, err
+			}
+
+			comps = append(comps, comp)
+			vals[comp] = val
+		}
+
+		order := owner.initializedOrder()
+		sort.SliceStable(comps, func(i, j int) bool {
+			return order(comps[i]) < order(comps[j])
+		})
+
+		for _, comp := range comps {
+			ts = append(ts, as[T](vals[comp]))
+		}
+	}
+
+	return ts, nil
+}
+
+// initializedOrder returns function returning position of component in order
+// components were initialized. Not singleton components placed after singletons
+func (c *Container) initializedOrder() func(*component) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	positions := make(map[*component]int, len(c.resolved))
+	for i, coord := range c.resolved {
+		positions[c.components[coord]] = i
+	}
+
+	return func(comp *component) int {
+		if pos, ok := positions[comp]; ok {
+			return pos
+		}
+		return len(positions)
+	}
+}
+
+// All returns all the components assignable to T. Use it within init function,
+// on error panic occurs which will be captured within Init function. See GetAll
+func All[T any](c *Container) []T {
+	ts, err := GetAll[T](c)
+	must(err)
+	return ts
+}
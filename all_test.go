@@ -0,0 +1,173 @@
+package di
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type allTestChecker interface{ check() string }
+
+type allTestDB struct{}
+type allTestCache struct{ name string }
+type allTestHealth struct{ checkers []allTestChecker }
+
+func (*allTestDB) check() string     { return "db" }
+func (c allTestCache) check() string { return c.name }
+
+func Test_All(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[*allTestHealth](c,
+		Init(func(c *Container) *allTestHealth {
+			return &allTestHealth{checkers: All[allTestChecker](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*allTestDB](c,
+		Init(func(c *Container) *allTestDB { return &allTestDB{} }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[allTestCache](c,
+		Name("users"),
+		Init(func(c *Container) allTestCache { return allTestCache{name: "users"} }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[allTestCache](c,
+		Name("sessions"),
+		Init(func(c *Container) allTestCache { return allTestCache{name: "sessions"} }),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	h, err := GetE[*allTestHealth](c)
+	require.NoError(t, err)
+
+	var names []string
+	for _, ch := range h.checkers {
+		names = append(names, ch.check())
+	}
+	require.Equal(t, []string{"db", "users", "sessions"}, names)
+
+	caches, err := GetAll[allTestCache](c)
+	require.NoError(t, err)
+	require.Equal(t, []allTestCache{{name: "users"}, {name: "sessions"}}, caches)
+
+	// dependencies recorded
+	require.Len(t, c.Graph().Edges, 3)
+}
+
+func Test_All_initialization_order(t *testing.T) {
+	c := NewContainer()
+
+	// users cache set first but initialized after sessions cache it depends on
+	err := Setup[allTestCache](c,
+		Name("users"),
+		Init(func(c *Container) allTestCache {
+			Get[allTestCache](c, Name("sessions"))
+			return allTestCache{name: "users"}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[allTestCache](c,
+		Name("sessions"),
+		Init(func(c *Container) allTestCache { return allTestCache{name: "sessions"} }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*allTestDB](c,
+		Lazy(),
+		Init(func(c *Container) *allTestDB { return &allTestDB{} }),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	checkers, err := GetAll[allTestChecker](c)
+	require.NoError(t, err)
+
+	var names []string
+	for _, ch := range checkers {
+		names = append(names, ch.check())
+	}
+	// lazy component initialized on request
+	require.Equal(t, []string{"sessions", "users", "db"}, names)
+}
+
+type allTestMulti struct{ checkers []allTestChecker }
+
+func (*allTestMulti) check() string { return "multi" }
+
+func Test_All_composite(t *testing.T) {
+	c := NewContainer()
+
+	// composite checker requests all the checkers except itself
+	err := Setup[*allTestMulti](c,
+		Init(func(c *Container) *allTestMulti {
+			return &allTestMulti{checkers: All[allTestChecker](c)}
+		}),
+		As[allTestChecker](),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*allTestDB](c,
+		Init(func(c *Container) *allTestDB { return &allTestDB{} }),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Init())
+
+	multi := Get[*allTestMulti](c)
+	require.Len(t, multi.checkers, 1)
+	require.Equal(t, "db", multi.checkers[0].check())
+
+	require.Len(t, All[allTestChecker](c), 2)
+}
+
+func Test_All_empty(t *testing.T) {
+	c := NewContainer()
+
+	err := c.Init()
+	require.NoError(t, err)
+
+	checkers, err := GetAll[allTestChecker](c)
+	require.NoError(t, err)
+	require.Empty(t, checkers)
+}
+
+func Test_All_error(t *testing.T) {
+	var (
+		c     = NewContainer()
+		dbErr = errors.New("db error")
+	)
+
+	err := Setup[*allTestHealth](c,
+		Init(func(c *Container) *allTestHealth {
+			return &allTestHealth{checkers: All[allTestChecker](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*allTestDB](c,
+		InitE(func(c *Container) (*allTestDB, error) { return nil, dbErr }),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.ErrorIs(t, err, dbErr)
+}
+
+func Test_GetAll_before_init(t *testing.T) {
+	c := NewContainer()
+
+	_, err := GetAll[allTestChecker](c)
+	require.ErrorIs(t, err, ErrNotInitialized)
+}
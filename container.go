@@ -11,6 +11,17 @@ type containerOpt interface {
 	applyContainerOpt(*Container)
 }
 
+type withParent struct {
+	parent *Container
+}
+
+func (o withParent) applyContainerOpt(c *Container) { c.parent = o.parent }
+
+// WithParent makes components not set within container looked up in parent.
+// Components set within container shadow parent components with same type and name.
+// Parent should be initialized before it's components requested
+func WithParent(parent *Container) withParent { return withParent{parent: parent} }
+
 type coordinate struct {
 	type_ reflect.Type
 	name  string
@@ -24,28 +35,124 @@ func (c coordinate) String() string {
 	return fmt.Sprintf("(%s, %s)", c.type_, name)
 }
 
+func (comp *component) String() string {
+	return componentString(comp.coord, comp.module)
+}
+
+func componentString(coord coordinate, module string) string {
+	if module == "" {
+		return coord.String()
+	}
+	return fmt.Sprintf("%s in module %s", coord, module)
+}
+
+type scope int
+
+const (
+	// single component instance initialized once
+	scopeSingleton scope = iota
+	// new component instance initialized on every request
+	scopeTransient
+	// single component instance initialized within scope
+	scopeScoped
+)
+
+func (s scope) String() string {
+	switch s {
+	case scopeTransient:
+		return "transient"
+	case scopeScoped:
+		return "scoped"
+	default:
+		return "singleton"
+	}
+}
+
 type component struct {
+	// mu guards initFn, val and err while component initialized
+	mu sync.Mutex
 	// initFn also used to indicate if component initialized
 	// if initFn is not nil component not initialized yet
 	// if initFn is nil component initialized
 	initFn func(*Container) (any, error)
 	val    any
+	// err returned from initFn
+	err error
+	// lazy component is not initialized on Init
+	// but on first request
+	lazy  bool
+	scope scope
+
+	coord coordinate
+	// module component set within. Empty if set outside of module
+	module string
+	// private component visible within it's module only
+	private bool
+	// stage functions by stage name
+	stages map[string]stage
+	// lifecycle hooks executed with Start and Stop
+	onStart func(context.Context, any) error
+	onStop  func(context.Context, any) error
+	// cleanup returned from constructor set with di.Provide. Called on Stop
+	cleanup func()
 }
 
-type Container struct {
+// state shared between container and it's views passed into init functions
+type state struct {
 	mu           sync.Mutex
 	initializing bool
 	initialized  bool
 
 	initOrder  []coordinate
-	components map[coordinate]component
-	stages     map[string][]func(context.Context) error
+	components map[coordinate]*component
+	// deps are components requested within component init function
+	deps map[coordinate][]coordinate
+	// resolved are components in order they were initialized.
+	// component placed after all of it's dependencies
+	resolved []coordinate
+	// stages are stage definitions by stage name
+	stages map[string]stageDef
+
+	// running is true after Start called and until Stop called
+	running bool
+	// started are components started with Start in order they were started
+	started []*component
+
+	// parent components are looked up in if not found within container
+	parent *Container
+	// modules are names of installed modules
+	modules map[string]bool
+
+	// waitMu guards inflight and resolutions waiting state
+	waitMu sync.Mutex
+	// inflight are resolutions initializing components by component or scoped instance
+	inflight map[any]*resolution
+}
+
+type Container struct {
+	*state
+
+	// path is chain of components being initialized. Container passed into
+	// init function holds path ending with component being initialized
+	path []coordinate
+	// scope scoped components resolved within. nil if container is not scope
+	scope *Scope
+	// module container acts within. Set for container passed into module setup
+	// functions and into init functions of module components
+	module string
+	// res is resolution container passed into init function belongs to
+	res *resolution
 }
 
 func NewContainer(opts ...containerOpt) *Container {
 	c := &Container{
-		components: make(map[coordinate]component),
-		stages:     make(map[string][]func(context.Context) error),
+		state: &state{
+			components: make(map[coordinate]*component),
+			deps:       make(map[coordinate][]coordinate),
+			stages:     make(map[string]stageDef),
+			modules:    make(map[string]bool),
+			inflight:   make(map[any]*resolution),
+		},
 	}
 
 	for _, o := range opts {
@@ -54,3 +161,62 @@ func NewContainer(opts ...containerOpt) *Container {
 
 	return c
 }
+
+// enter returns container view to pass into init function of component
+func (c *Container) enter(comp *component) *Container {
+	path := make([]coordinate, len(c.path), len(c.path)+1)
+	copy(path, c.path)
+
+	view := &Container{
+		state:  c.state,
+		path:   append(path, comp.coord),
+		module: comp.module,
+		res:    c.res,
+	}
+
+	// singleton should not depend on scoped components
+	// as it outlives scope
+	if comp.scope != scopeSingleton {
+		view.scope = c.scope
+	}
+
+	return view
+}
+
+// lookup finds component within container or it's parents.
+// Returns container component set within
+func (c *Container) lookup(coord coordinate) (*Container, *component, bool) {
+	if comp, ok := c.components[coord]; ok {
+		return c, comp, true
+	}
+
+	if c.parent == nil {
+		return nil, nil, false
+	}
+
+	return c.parent.lookup(coord)
+}
+
+// chain returns container parents from root to container itself
+func (c *Container) chain() []*Container {
+	if c.parent == nil {
+		return []*Container{c}
+	}
+
+	return append(c.parent.chain(), c)
+}
+
+// visible checks if component found within owner container can be resolved
+// with container. Parent scoped components are not resolved within child scope.
+// Private components resolved within their module only
+func (c *Container) visible(owner *Container, comp *component) bool {
+	if comp.private && comp.module != c.module {
+		return false
+	}
+
+	if owner.state != c.state {
+		return comp.scope != scopeScoped
+	}
+
+	return c.inScope(comp)
+}
@@ -0,0 +1,102 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type parentTestConfig struct{ tenant string }
+type parentTestDB struct{ config *parentTestConfig }
+type parentTestService struct{ db *parentTestDB }
+
+func Test_parent_lookup(t *testing.T) {
+	parent := NewContainer()
+	require.NoError(t, Value(parent, &parentTestDB{}))
+	require.NoError(t, parent.Init())
+
+	child := NewContainer(WithParent(parent))
+
+	err := Setup[*parentTestService](child,
+		Init(func(c *Container) *parentTestService {
+			return &parentTestService{db: Get[*parentTestDB](c)}
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, child.Init())
+
+	svc, err := GetE[*parentTestService](child)
+	require.NoError(t, err)
+	require.Same(t, Get[*parentTestDB](parent), svc.db)
+
+	_, err = GetE[*parentTestService](parent)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_parent_shadow(t *testing.T) {
+	parent := NewContainer()
+
+	err := Setup[*parentTestConfig](parent,
+		Init(func(c *Container) *parentTestConfig { return &parentTestConfig{tenant: "default"} }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*parentTestDB](parent,
+		Init(func(c *Container) *parentTestDB {
+			return &parentTestDB{config: Get[*parentTestConfig](c)}
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, parent.Init())
+
+	child := NewContainer(WithParent(parent))
+
+	err = Setup[*parentTestConfig](child,
+		Init(func(c *Container) *parentTestConfig { return &parentTestConfig{tenant: "tenant"} }),
+	)
+	require.NoError(t, err)
+	require.NoError(t, child.Init())
+
+	require.Equal(t, "tenant", Get[*parentTestConfig](child).tenant)
+	require.Equal(t, "default", Get[*parentTestConfig](parent).tenant)
+	// parent component initialized within parent
+	require.Equal(t, "default", Get[*parentTestDB](child).config.tenant)
+
+	require.Equal(t, []*parentTestConfig{Get[*parentTestConfig](child)}, All[*parentTestConfig](child))
+}
+
+func Test_parent_not_initialized(t *testing.T) {
+	parent := NewContainer()
+	err := Setup[*parentTestConfig](parent,
+		Init(func(c *Container) *parentTestConfig { return &parentTestConfig{} }),
+	)
+	require.NoError(t, err)
+
+	child := NewContainer(WithParent(parent))
+	require.NoError(t, child.Init())
+
+	_, err = GetE[*parentTestConfig](child)
+	require.ErrorIs(t, err, ErrNotInitialized)
+}
+
+func Test_parent_graph(t *testing.T) {
+	parent := NewContainer()
+	require.NoError(t, Value(parent, &parentTestDB{}))
+	require.NoError(t, parent.Init())
+
+	child := NewContainer(WithParent(parent))
+
+	err := Setup[*parentTestService](child,
+		Init(func(c *Container) *parentTestService {
+			return &parentTestService{db: Get[*parentTestDB](c)}
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, child.Init())
+
+	g := child.Graph()
+	require.Len(t, g.Nodes, 2)
+	require.Len(t, g.Edges, 1)
+	require.Equal(t, "(*di.parentTestService, (Unnamed))", g.Edges[0].From.String())
+	require.Equal(t, "(*di.parentTestDB, (Unnamed))", g.Edges[0].To.String())
+}
@@ -0,0 +1,98 @@
+// Package ditest provides helpers to use di container within tests
+package ditest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sabahtalateh/di"
+)
+
+// Container is di container bound to test
+type Container struct {
+	*di.Container
+	t testing.TB
+}
+
+// Option is option of Provide and AssertResolvable
+type Option struct {
+	name string
+}
+
+// Name defines name of component
+func Name(name string) Option { return Option{name: name} }
+
+func processOpts(opts ...Option) Option {
+	var o Option
+	for _, opt := range opts {
+		if opt.name != "" {
+			o.name = opt.name
+		}
+	}
+	return o
+}
+
+// New creates container. On test cleanup "stop" stage executed if container
+// initialized and Stop called so cleanups of components built are called
+// even if Init failed
+func New(t testing.TB) *Container {
+	c := &Container{Container: di.NewContainer(), t: t}
+	t.Cleanup(c.cleanup)
+	return c
+}
+
+func (c *Container) cleanup() {
+	ctx := context.Background()
+
+	err := c.ExecStage(ctx, "stop")
+	if errors.Is(err, di.ErrNotInitialized) {
+		err = nil
+	}
+
+	if err = errors.Join(err, c.Stop(ctx)); err != nil {
+		c.t.Errorf("di: stop container:\n%s", err)
+	}
+}
+
+// Init inits container. Test fails on error
+func (c *Container) Init() {
+	c.t.Helper()
+
+	if err := c.Container.Init(); err != nil {
+		c.t.Fatalf("di: init container:\n%s", err)
+	}
+}
+
+// Provide sets value as component of type T. Component set before
+// with di.Setup replaced. Test fails on error
+func Provide[T any](c *Container, v T, opts ...Option) {
+	c.t.Helper()
+
+	o := processOpts(opts...)
+
+	err := di.Replace[T](c.Container, di.Name(o.name), di.Init(func(*di.Container) T { return v }))
+	if errors.Is(err, di.ErrNotFound) {
+		err = di.Value(c.Container, v, di.Name(o.name))
+	}
+
+	if err != nil {
+		c.t.Fatalf("di: provide %T:\n%s", v, err)
+	}
+}
+
+// AssertResolvable checks component of type T can be retrieved from
+// initialized container and returns it. Test marked failed on error
+func AssertResolvable[T any](c *Container, opts ...Option) T {
+	c.t.Helper()
+
+	o := processOpts(opts...)
+
+	t, err := di.GetE[T](c.Container, di.Name(o.name))
+	if err != nil {
+		c.t.Errorf("di: resolve (%s, %q):\n%s", reflect.TypeOf(&t).Elem(), o.name, err)
+	}
+
+	return t
+}
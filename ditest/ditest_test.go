@@ -0,0 +1,167 @@
+package ditest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sabahtalateh/di"
+)
+
+// fakeT records failures and cleanups instead of failing test
+type fakeT struct {
+	testing.TB
+	errors   []string
+	fatal    bool
+	cleanups []func()
+}
+
+func (t *fakeT) Helper()           {}
+func (t *fakeT) Cleanup(fn func()) { t.cleanups = append(t.cleanups, fn) }
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+	t.fatal = true
+}
+
+func (t *fakeT) cleanup() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+type testDB struct{ name string }
+type testRepo struct{ db *testDB }
+
+func Test_Provide(t *testing.T) {
+	var (
+		ft      = &fakeT{}
+		c       = New(ft)
+		stopped []string
+	)
+
+	err := di.Setup[*testDB](c.Container,
+		di.Init(func(c *di.Container) *testDB { return &testDB{name: "real"} }),
+	)
+	require.NoError(t, err)
+
+	err = di.Setup[*testRepo](c.Container,
+		di.Init(func(c *di.Container) *testRepo { return &testRepo{db: di.Get[*testDB](c)} }),
+		di.Stage("stop", func(ctx context.Context, r *testRepo) error {
+			stopped = append(stopped, r.db.name)
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	Provide(c, &testDB{name: "fake"})
+	Provide(c, &testDB{name: "replica"}, Name("replica"))
+	c.Init()
+
+	require.Equal(t, "fake", AssertResolvable[*testRepo](c).db.name)
+	require.Equal(t, "replica", AssertResolvable[*testDB](c, Name("replica")).name)
+
+	ft.cleanup()
+	require.Equal(t, []string{"fake"}, stopped)
+	require.Empty(t, ft.errors)
+	require.False(t, ft.fatal)
+}
+
+type testQuerier interface{ Query() string }
+
+func (db *testDB) Query() string { return db.name }
+
+type testFakeQuerier struct{}
+
+func (testFakeQuerier) Query() string { return "fake" }
+
+func Test_Provide_alias(t *testing.T) {
+	var (
+		ft = &fakeT{}
+		c  = New(ft)
+	)
+
+	err := di.Setup[*testDB](c.Container,
+		di.Init(func(c *di.Container) *testDB { return &testDB{name: "real"} }),
+		di.As[testQuerier](),
+	)
+	require.NoError(t, err)
+
+	Provide[testQuerier](c, testFakeQuerier{})
+	c.Init()
+
+	require.Equal(t, "fake", AssertResolvable[testQuerier](c).Query())
+	// component alias taken off kept
+	require.Equal(t, "real", AssertResolvable[*testDB](c).Query())
+	require.Empty(t, ft.errors)
+	require.False(t, ft.fatal)
+
+	ft.cleanup()
+	require.Empty(t, ft.errors)
+
+	ft = &fakeT{}
+	c = New(ft)
+
+	err = di.Setup[*testDB](c.Container,
+		di.Init(func(c *di.Container) *testDB { return &testDB{name: "real"} }),
+		di.As[testQuerier](),
+	)
+	require.NoError(t, err)
+
+	// alias kept when component replaced
+	Provide(c, &testDB{name: "fake"})
+	c.Init()
+
+	require.Equal(t, "fake", AssertResolvable[testQuerier](c).Query())
+	require.Empty(t, ft.errors)
+	require.False(t, ft.fatal)
+}
+
+func Test_Init_fails(t *testing.T) {
+	var (
+		ft      = &fakeT{}
+		c       = New(ft)
+		cleaned = false
+	)
+
+	err := di.Provide(c.Container, func() (*testDB, func(), error) {
+		return &testDB{}, func() { cleaned = true }, nil
+	})
+	require.NoError(t, err)
+
+	err = di.Setup[*testRepo](c.Container,
+		di.InitE(func(c *di.Container) (*testRepo, error) {
+			di.Get[*testDB](c)
+			return nil, errors.New("connect")
+		}),
+	)
+	require.NoError(t, err)
+
+	c.Init()
+	require.True(t, ft.fatal)
+	require.Len(t, ft.errors, 1)
+	require.Contains(t, ft.errors[0], "di: init container")
+
+	// components built before error stopped
+	ft.cleanup()
+	require.Len(t, ft.errors, 1)
+	require.True(t, cleaned)
+}
+
+func Test_AssertResolvable_fails(t *testing.T) {
+	ft := &fakeT{}
+	c := New(ft)
+	c.Init()
+
+	AssertResolvable[*testDB](c, Name("primary"))
+	require.Len(t, ft.errors, 1)
+	require.Contains(t, ft.errors[0], `di: resolve (*ditest.testDB, "primary")`)
+	require.False(t, ft.fatal)
+}
@@ -3,36 +3,102 @@ package di
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"runtime/debug"
 )
 
 var (
-	ErrInitialized    = fmt.Errorf("initialized")
-	ErrNotInitialized = fmt.Errorf("not initialized")
-	ErrComponentSet   = fmt.Errorf("component set")
-	ErrNameSet        = fmt.Errorf("name set")
-	ErrInitSet        = fmt.Errorf("init function set")
-	ErrInitNotSet     = fmt.Errorf("init function not set")
-	ErrStageSet       = fmt.Errorf("stage set")
-	ErrStageNotSet    = fmt.Errorf("stage not set")
-	ErrExecuteStage   = fmt.Errorf("execute stage")
-	ErrDisordered     = fmt.Errorf("disordered setup")
-	ErrNotFound       = fmt.Errorf("not found")
+	ErrInitialized      = fmt.Errorf("initialized")
+	ErrNotInitialized   = fmt.Errorf("not initialized")
+	ErrComponentSet     = fmt.Errorf("component set")
+	ErrNotAssignable    = fmt.Errorf("not assignable")
+	ErrNameSet          = fmt.Errorf("name set")
+	ErrScopeSet         = fmt.Errorf("scope set")
+	ErrScopeStage       = fmt.Errorf("stage set for not singleton")
+	ErrOutOfScope       = fmt.Errorf("out of scope")
+	ErrScopeClosed      = fmt.Errorf("scope closed")
+	ErrWithinScope      = fmt.Errorf("not allowed within scope")
+	ErrInitSet          = fmt.Errorf("init function set")
+	ErrInitNotSet       = fmt.Errorf("init function not set")
+	ErrInitComponent    = fmt.Errorf("init component")
+	ErrConstructor      = fmt.Errorf("invalid constructor")
+	ErrInject           = fmt.Errorf("inject")
+	ErrModuleInstalled  = fmt.Errorf("module installed")
+	ErrModuleNameNotSet = fmt.Errorf("module name not set")
+	ErrStageSet         = fmt.Errorf("stage set")
+	ErrStageNotSet      = fmt.Errorf("stage not set")
+	ErrStageConflict    = fmt.Errorf("stage conflict")
+	ErrExecuteStage     = fmt.Errorf("execute stage")
+	ErrStageTimeout     = fmt.Errorf("stage timeout")
+	ErrStarted          = fmt.Errorf("started")
+	ErrStart            = fmt.Errorf("start")
+	ErrStop             = fmt.Errorf("stop")
+	ErrShutdownTimeout  = fmt.Errorf("shutdown timeout")
+	ErrShutdownForced   = fmt.Errorf("shutdown forced")
+	ErrCycle            = fmt.Errorf("dependency cycle")
+	ErrNotFound         = fmt.Errorf("not found")
+	ErrNotVisible       = fmt.Errorf("not visible")
 
 	recoverableErrs = []error{
 		ErrInitialized,
 		ErrNotInitialized,
 		ErrComponentSet,
+		ErrNotAssignable,
 		ErrNameSet,
+		ErrScopeSet,
+		ErrScopeStage,
+		ErrOutOfScope,
+		ErrScopeClosed,
+		ErrWithinScope,
 		ErrInitSet,
 		ErrInitNotSet,
+		ErrInitComponent,
+		ErrConstructor,
+		ErrInject,
+		ErrModuleInstalled,
+		ErrModuleNameNotSet,
 		ErrStageSet,
 		ErrStageNotSet,
+		ErrStageConflict,
 		ErrExecuteStage,
-		ErrDisordered,
+		ErrStageTimeout,
+		ErrStarted,
+		ErrStart,
+		ErrStop,
+		ErrShutdownTimeout,
+		ErrShutdownForced,
+		ErrCycle,
 		ErrNotFound,
+		ErrNotVisible,
 	}
 )
 
+// Deprecated: use ErrCycle. ErrDisordered is alias of ErrCycle
+var ErrDisordered = ErrCycle
+
+// StageError is error returned from component stage function.
+// Matches ErrExecuteStage with errors.Is
+type StageError struct {
+	// Stage is name of stage
+	Stage string
+	// Type and Name of component
+	Type reflect.Type
+	Name string
+	// Module component set within
+	Module string
+	Err    error
+}
+
+func newStageError(stage string, comp *component, err error) *StageError {
+	return &StageError{Stage: stage, Type: comp.coord.type_, Name: comp.coord.name, Module: comp.module, Err: err}
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("%s: %s: %s: %s", ErrExecuteStage, e.Stage, componentString(coordinate{type_: e.Type, name: e.Name}, e.Module), e.Err)
+}
+
+func (e *StageError) Unwrap() []error { return []error{ErrExecuteStage, e.Err} }
+
 func recoverable(err error) bool {
 	for _, e := range recoverableErrs {
 		if errors.Is(err, e) {
@@ -52,3 +118,24 @@ func toError(x any) (err error) {
 	}
 	return err
 }
+
+// stackError holds stack trace of place where error occurred
+type stackError struct {
+	err   error
+	stack []byte
+}
+
+func (e *stackError) Error() string { return fmt.Sprintf("%s: %s", e.err, e.stack) }
+func (e *stackError) Unwrap() error { return e.err }
+
+// withStack adds stack trace to error if it has no one yet.
+// Error may already have stack trace when it raised within
+// init function of component initialized on demand
+func withStack(err error) error {
+	var se *stackError
+	if errors.As(err, &se) {
+		return err
+	}
+
+	return &stackError{err: err, stack: debug.Stack()}
+}
@@ -0,0 +1,78 @@
+package di
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes components dependency graph in Graphviz DOT format
+func (c *Container) WriteDOT(w io.Writer) error {
+	var (
+		g   = c.Graph()
+		ids = nodeIDs(g)
+		b   strings.Builder
+	)
+
+	b.WriteString("digraph di {\n")
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	for _, n := range g.Nodes {
+		lines := nodeLabel(n)
+		for i := range lines {
+			lines[i] = escape.Replace(lines[i])
+		}
+		label := strings.Join(lines, `\n`)
+		fmt.Fprintf(&b, "\t%s [shape=box, label=\"%s\"];\n", ids[n], label)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s;\n", ids[e.From], ids[e.To])
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid writes components dependency graph as Mermaid flowchart
+func (c *Container) WriteMermaid(w io.Writer) error {
+	var (
+		g   = c.Graph()
+		ids = nodeIDs(g)
+		b   strings.Builder
+	)
+
+	b.WriteString("flowchart TD\n")
+	for _, n := range g.Nodes {
+		label := strings.ReplaceAll(strings.Join(nodeLabel(n), "<br/>"), `"`, "#quot;")
+		fmt.Fprintf(&b, "\t%s[\"%s\"]\n", ids[n], label)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s --> %s\n", ids[e.From], ids[e.To])
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func nodeIDs(g Graph) map[*Node]string {
+	ids := make(map[*Node]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
+
+// nodeLabel returns node label lines: type, name if set, stages if any
+func nodeLabel(n *Node) []string {
+	lines := []string{n.Type.String()}
+	if n.Name != "" {
+		lines = append(lines, fmt.Sprintf("name: %s", n.Name))
+	}
+	if n.Module != "" {
+		lines = append(lines, fmt.Sprintf("module: %s", n.Module))
+	}
+	if len(n.Stages) > 0 {
+		lines = append(lines, fmt.Sprintf("stages: %s", strings.Join(n.Stages, ", ")))
+	}
+	return lines
+}
@@ -0,0 +1,100 @@
+package di
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Export(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func() (*Container, error)
+		write func(c *Container, w io.Writer) error
+		want  string
+	}{
+		{
+			name: "dot",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*graphTestRepo](c,
+					Init(func(c *Container) *graphTestRepo {
+						Get[*graphTestDB](c, Name("primary"))
+						return &graphTestRepo{}
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*graphTestDB](c,
+					Name("primary"),
+					Init(func(c *Container) *graphTestDB { return &graphTestDB{} }),
+					Stage("stop", func(ctx context.Context, db *graphTestDB) error { return nil }),
+					Stage("start", func(ctx context.Context, db *graphTestDB) error { return nil }),
+					OnStop(func(ctx context.Context, db *graphTestDB) error { return nil }),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			write: (*Container).WriteDOT,
+			want: `digraph di {
+	n0 [shape=box, label="*di.graphTestRepo"];
+	n1 [shape=box, label="*di.graphTestDB\nname: primary\nstages: start, stop, on stop"];
+	n0 -> n1;
+}
+`,
+		},
+		{
+			name: "mermaid",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*graphTestRepo](c,
+					Init(func(c *Container) *graphTestRepo {
+						Get[*graphTestDB](c, Name("primary"))
+						return &graphTestRepo{}
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*graphTestDB](c,
+					Name("primary"),
+					Init(func(c *Container) *graphTestDB { return &graphTestDB{} }),
+					Stage("stop", func(ctx context.Context, db *graphTestDB) error { return nil }),
+					Stage("start", func(ctx context.Context, db *graphTestDB) error { return nil }),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			write: (*Container).WriteMermaid,
+			want: `flowchart TD
+	n0["*di.graphTestRepo"]
+	n1["*di.graphTestDB<br/>name: primary<br/>stages: start, stop"]
+	n0 --> n1
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.setup()
+			require.NoError(t, err)
+			require.NoError(t, c.Init())
+
+			var b strings.Builder
+			require.NoError(t, tt.write(c, &b))
+			require.Equal(t, tt.want, b.String())
+		})
+	}
+}
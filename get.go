@@ -24,22 +24,25 @@ func (c *Container) checkGet() error {
 
 func Get[T any](c *Container, opts ...getOpt[T]) T {
 	t, err := GetE(c, opts...)
+	must(err)
+	return t
+}
+
+// must panics on error. di errors converted into error when happens while init. see Init
+func must(err error) {
 	if err != nil {
-		// di errors converted into error when happens while init. see Init
 		panic(err)
 	}
-	return t
 }
 
-func GetE[T any](c *Container, opts ...getOpt[T]) (T, error) {
-	var (
-		t T
-	)
-
-	if err := c.checkGet(); err != nil {
-		return t, err
-	}
+// as returns component value as T. nil value of interface type
+// can not be asserted so zero value returned
+func as[T any](val any) T {
+	t, _ := val.(T)
+	return t
+}
 
+func processGetOpts[T any](opts ...getOpt[T]) (string, error) {
 	var (
 		nameSet = false
 		name    = ""
@@ -49,49 +52,74 @@ func GetE[T any](c *Container, opts ...getOpt[T]) (T, error) {
 		switch o := o.(type) {
 		case withName:
 			if nameSet {
-				return t, ErrNameSet
+				return "", ErrNameSet
 			}
 			name = string(o)
 			nameSet = true
 		}
 	}
 
+	return name, nil
+}
+
+func GetE[T any](c *Container, opts ...getOpt[T]) (T, error) {
+	var (
+		t T
+	)
+
+	if err := c.checkGet(); err != nil {
+		return t, err
+	}
+
+	name, err := processGetOpts(opts...)
+	if err != nil {
+		return t, err
+	}
+
 	coord := coordinate{
 		type_: reflect.TypeOf(&t).Elem(),
 		name:  name,
 	}
 
-	comp, ok := c.components[coord]
-	if !ok {
-		return t, errNotFoundWithHint[T](c, coord.name)
+	val, err := c.get(coord)
+	if err != nil {
+		return t, err
 	}
 
-	if comp.initFn != nil {
-		return t, fmt.Errorf("%w: %s must be set before parent component", ErrDisordered, coord)
-	}
+	return as[T](val), nil
+}
 
-	t, ok = comp.val.(T)
+// get returns component value by coordinate
+func (c *Container) get(coord coordinate) (any, error) {
+	owner, comp, ok := c.lookup(coord)
 	if !ok {
-		return t, ErrNotFound
+		return nil, errNotFoundWithHint(c, coord)
 	}
 
-	return t, nil
+	if comp.private && comp.module != c.module {
+		return nil, errNotVisible(c, comp)
+	}
+
+	return c.resolveAt(owner, comp)
 }
 
-func errNotFoundWithHint[T any](c *Container, name string) error {
-	tryCoord := coordinate{name: name}
+func errNotVisible(c *Container, comp *component) error {
+	if c.module == "" {
+		return fmt.Errorf("%w: %s outside of module", ErrNotVisible, comp)
+	}
+	return fmt.Errorf("%w: %s from module %s", ErrNotVisible, comp, c.module)
+}
 
-	var t T
-	pt := &t
-	type_ := reflect.TypeOf(pt).Elem()
+func errNotFoundWithHint(c *Container, coord coordinate) error {
+	tryCoord := coordinate{name: coord.name}
 
-	if type_.Kind() == reflect.Pointer {
-		tryCoord.type_ = type_.Elem()
+	if coord.type_.Kind() == reflect.Pointer {
+		tryCoord.type_ = coord.type_.Elem()
 	} else {
-		tryCoord.type_ = reflect.TypeOf(&pt).Elem()
+		tryCoord.type_ = reflect.PointerTo(coord.type_)
 	}
 
-	if _, ok := c.components[tryCoord]; ok {
+	if _, _, ok := c.lookup(tryCoord); ok {
 		return fmt.Errorf("%w: found component %s", ErrNotFound, tryCoord)
 	}
 
@@ -257,3 +257,87 @@ func Test_get_stack_trace(t *testing.T) {
 	// test err contains stack trace with file path
 	require.Contains(t, err.Error(), "di/get_test.go:")
 }
+
+type getTestInterface interface{ get() }
+
+func (*getTestType) get() {}
+
+func Test_get_as_interface(t *testing.T) {
+	var (
+		c    = NewContainer()
+		impl = &getTestType{}
+	)
+
+	err := Setup[*getTestType](c,
+		Name("A"),
+		Init(func(c *Container) *getTestType { return impl }),
+		As[getTestInterface](),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	got, err := GetE[getTestInterface](c, Name("A"))
+	require.NoError(t, err)
+	require.Same(t, impl, got)
+
+	gotImpl, err := GetE[*getTestType](c, Name("A"))
+	require.NoError(t, err)
+	require.Same(t, impl, gotImpl)
+}
+
+func Test_get_as_interface_initialized_once(t *testing.T) {
+	var (
+		c     = NewContainer()
+		calls = 0
+	)
+
+	err := Setup[*getTestType](c,
+		Init(func(c *Container) *getTestType {
+			calls++
+			return &getTestType{}
+		}),
+		As[getTestInterface](),
+	)
+	require.NoError(t, err)
+
+	err = Setup[getTestType](c,
+		Init(func(c *Container) getTestType {
+			Get[getTestInterface](c)
+			return getTestType{}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+
+	// dependency recorded on component
+	g := c.Graph()
+	require.Len(t, g.Nodes, 2)
+	require.Equal(t, []Edge{{From: g.Nodes[1], To: g.Nodes[0]}}, g.Edges)
+}
+
+func Test_get_nil_interface(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[getTestInterface](c,
+		Init(func(c *Container) getTestInterface { return nil }),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	// nil value of interface type returned as zero value
+	got, err := GetE[getTestInterface](c)
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	got, ok, err := GetOptionalE[getTestInterface](c)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Nil(t, got)
+}
@@ -0,0 +1,91 @@
+package di
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Graph is components dependency graph. Dependency recorded
+// when component requested with Get/GetE within init function
+// so graph is complete after container initialized
+type Graph struct {
+	// Nodes in order corresponding di.Setup were called. Parent components
+	// container components depend on placed after container components
+	Nodes []*Node
+	Edges []Edge
+}
+
+// Node is component set with di.Setup
+type Node struct {
+	Type reflect.Type
+	Name string
+	// Module component set within. Empty if set outside of module
+	Module string
+	// Stages component participates in sorted by name followed
+	// by "on start" and "on stop" if lifecycle hooks defined
+	Stages []string
+}
+
+func (n *Node) String() string {
+	return coordinate{type_: n.Type, name: n.Name}.String()
+}
+
+// Edge is dependency of component From on component To
+type Edge struct {
+	From *Node
+	To   *Node
+}
+
+func (c *Container) Graph() Graph {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var (
+		g     = Graph{Nodes: make([]*Node, 0, len(c.initOrder))}
+		nodes = make(map[coordinate]*Node, len(c.initOrder))
+	)
+
+	for _, coord := range c.initOrder {
+		n := newNode(c.components[coord])
+		g.Nodes = append(g.Nodes, n)
+		nodes[coord] = n
+	}
+
+	for _, coord := range c.initOrder {
+		for _, dep := range c.deps[coord] {
+			to, ok := nodes[dep]
+			if !ok && c.parent != nil {
+				// dependency set within parent
+				_, comp, _ := c.parent.lookup(dep)
+				to = newNode(comp)
+				g.Nodes = append(g.Nodes, to)
+				nodes[dep] = to
+			}
+			g.Edges = append(g.Edges, Edge{From: nodes[coord], To: to})
+		}
+	}
+
+	return g
+}
+
+func newNode(comp *component) *Node {
+	n := &Node{
+		Type:   comp.coord.type_,
+		Name:   comp.coord.name,
+		Module: comp.module,
+		Stages: make([]string, 0, len(comp.stages)+2),
+	}
+	for stage := range comp.stages {
+		n.Stages = append(n.Stages, stage)
+	}
+	sort.Strings(n.Stages)
+
+	if comp.onStart != nil {
+		n.Stages = append(n.Stages, "on start")
+	}
+	if comp.onStop != nil {
+		n.Stages = append(n.Stages, "on stop")
+	}
+
+	return n
+}
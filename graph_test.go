@@ -0,0 +1,125 @@
+package di
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type graphTestDB struct{}
+type graphTestRepo struct{}
+type graphTestHandler struct{}
+
+func Test_Graph(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func() (*Container, error)
+		init       bool
+		wantNodes  []string
+		wantStages [][]string
+		wantEdges  [][2]int
+	}{
+		{
+			name: "dependencies recorded on init",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*graphTestHandler](c,
+					Init(func(c *Container) *graphTestHandler {
+						// requested twice but recorded once
+						Get[*graphTestRepo](c)
+						Get[*graphTestRepo](c)
+						return &graphTestHandler{}
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*graphTestRepo](c,
+					Init(func(c *Container) *graphTestRepo {
+						Get[*graphTestDB](c, Name("primary"))
+						return &graphTestRepo{}
+					}),
+					OnStart(func(ctx context.Context, r *graphTestRepo) error { return nil }),
+					OnStop(func(ctx context.Context, r *graphTestRepo) error { return nil }),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*graphTestDB](c,
+					Name("primary"),
+					Init(func(c *Container) *graphTestDB { return &graphTestDB{} }),
+					Stage("stop", func(ctx context.Context, db *graphTestDB) error { return nil }),
+					Stage("start", func(ctx context.Context, db *graphTestDB) error { return nil }),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			init: true,
+			wantNodes: []string{
+				"(*di.graphTestHandler, (Unnamed))",
+				"(*di.graphTestRepo, (Unnamed))",
+				"(*di.graphTestDB, primary)",
+			},
+			wantStages: [][]string{{}, {"on start", "on stop"}, {"start", "stop"}},
+			// handler not depends on db through repo
+			wantEdges: [][2]int{{0, 1}, {1, 2}},
+		},
+		{
+			name: "no edges before init",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*graphTestRepo](c,
+					Init(func(c *Container) *graphTestRepo {
+						Get[*graphTestDB](c)
+						return &graphTestRepo{}
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Value(c, &graphTestDB{})
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			wantNodes: []string{
+				"(*di.graphTestRepo, (Unnamed))",
+				"(*di.graphTestDB, (Unnamed))",
+			},
+			wantStages: [][]string{{}, {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.setup()
+			require.NoError(t, err)
+
+			if tt.init {
+				require.NoError(t, c.Init())
+			}
+
+			g := c.Graph()
+
+			require.Len(t, g.Nodes, len(tt.wantNodes))
+			for i, n := range g.Nodes {
+				require.Equal(t, tt.wantNodes[i], n.String())
+				require.Equal(t, tt.wantStages[i], n.Stages)
+			}
+
+			require.Len(t, g.Edges, len(tt.wantEdges))
+			for i, e := range tt.wantEdges {
+				require.Equal(t, Edge{From: g.Nodes[e[0]], To: g.Nodes[e[1]]}, g.Edges[i])
+			}
+		})
+	}
+}
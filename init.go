@@ -2,7 +2,9 @@ package di
 
 import (
 	"fmt"
-	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
 )
 
 func (c *Container) enterInit() error {
@@ -33,36 +35,217 @@ func (c *Container) exitInit() {
 func InitE[T any](f func(*Container) (T, error)) withInitE[T] { return f }
 func Init[T any](f func(*Container) T) withInit[T]            { return f }
 
-func (c *Container) Init() (err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%w: %s", toError(r), debug.Stack())
-			if !recoverable(err) {
-				panic(err)
-			}
-		}
-	}()
+func (c *Container) Init() error {
+	if err := c.checkNotScope(); err != nil {
+		return err
+	}
 
-	if err = c.enterInit(); err != nil {
+	if err := c.enterInit(); err != nil {
 		return err
 	}
 
 	for _, coord := range c.initOrder {
 		comp, ok := c.components[coord]
-		if !ok {
+		if !ok || comp.lazy || comp.scope != scopeSingleton {
 			continue
 		}
 
-		comp.val, err = comp.initFn(c)
-		if err != nil {
+		if _, err := c.resolve(comp); err != nil {
 			return err
 		}
-		comp.initFn = nil
-
-		c.components[coord] = comp
 	}
 
 	c.exitInit()
 
-	return err
+	return nil
+}
+
+// resolve returns component value. If component not initialized yet
+// it's init function called. Components requested with Get within init
+// function initialized on demand so setup order does not matter.
+// Init function called once, error returned from it returned on every resolve.
+// Init function of transient component called on every resolve.
+// Scoped component resolved within scope only
+func (c *Container) resolve(comp *component) (any, error) {
+	c.dependsOn(comp.coord)
+
+	// checked before lock, component from path is locked while initializing
+	for i, coord := range c.path {
+		if coord == comp.coord {
+			return nil, errCycle(append(c.path[i:len(c.path):len(c.path)], comp.coord))
+		}
+	}
+
+	if c.res == nil {
+		view := *c
+		view.res = &resolution{}
+		c = &view
+	}
+
+	switch comp.scope {
+	case scopeTransient:
+		return c.initComponent(comp)
+	case scopeScoped:
+		if c.scope == nil {
+			return nil, fmt.Errorf("%w: %s", ErrOutOfScope, comp.coord)
+		}
+		return c.scope.resolve(c, comp)
+	}
+
+	unlock, err := c.lock(comp, &comp.mu, comp.coord)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if comp.initFn == nil {
+		return comp.val, comp.err
+	}
+
+	comp.val, comp.err = c.initComponent(comp)
+	comp.initFn = nil
+	if comp.err != nil {
+		return nil, comp.err
+	}
+
+	c.mu.Lock()
+	c.resolved = append(c.resolved, comp.coord)
+	c.mu.Unlock()
+
+	return comp.val, nil
+}
+
+// resolution is chain of components initialized one within other's init function
+// started with single resolve. Used to detect cycle of components initialized
+// concurrently as every resolution waits for other to release component
+type resolution struct {
+	// waiting is component or scoped instance resolution waits for
+	// with coordinate of component and path resolution waits with
+	waiting any
+	coord   coordinate
+	path    []coordinate
+}
+
+// lock locks component or scoped instance mutex. If it is locked by other resolution
+// waiting for component locked by resolution of container cycle error returned
+func (c *Container) lock(key any, mu *sync.Mutex, coord coordinate) (func(), error) {
+	c.waitMu.Lock()
+	if cycle := c.waitCycle(key, coord); cycle != nil {
+		c.waitMu.Unlock()
+		return nil, errCycle(cycle)
+	}
+	c.res.waiting, c.res.coord, c.res.path = key, coord, c.path
+	c.waitMu.Unlock()
+
+	mu.Lock()
+
+	c.waitMu.Lock()
+	c.res.waiting, c.res.path = nil, nil
+	c.inflight[key] = c.res
+	c.waitMu.Unlock()
+
+	return func() {
+		c.waitMu.Lock()
+		delete(c.inflight, key)
+		c.waitMu.Unlock()
+
+		mu.Unlock()
+	}, nil
+}
+
+// waitCycle follows resolutions waiting one for other starting with one locked key.
+// Returns components chain if it leads to resolution of container. waitMu should be locked
+func (c *Container) waitCycle(key any, coord coordinate) []coordinate {
+	chain := append(slices.Clone(c.path), coord)
+
+	// every resolution visited once at most
+	for range c.inflight {
+		owner, ok := c.inflight[key]
+		if !ok || owner.waiting == nil {
+			return nil
+		}
+
+		// owner path continues with components initialized within locked one
+		chain = append(chain, owner.path[slices.Index(owner.path, coord)+1:]...)
+		chain = append(chain, owner.coord)
+		key, coord = owner.waiting, owner.coord
+
+		if c.inflight[key] == c.res {
+			return chain[max(slices.Index(c.path, coord), 0):]
+		}
+	}
+
+	return nil
+}
+
+// resolveAt resolves component found with lookup within owner container.
+// Parent component resolved within parent so it is initialized once for all the children
+func (c *Container) resolveAt(owner *Container, comp *component) (any, error) {
+	if owner.state == c.state {
+		return c.resolve(comp)
+	}
+
+	c.dependsOn(comp.coord)
+
+	if err := owner.checkGet(); err != nil {
+		return nil, fmt.Errorf("%w: parent of %s", err, comp.coord)
+	}
+
+	return owner.resolve(comp)
+}
+
+// initComponent calls component init function
+func (c *Container) initComponent(comp *component) (any, error) {
+	val, err := callInit(c.enter(comp), comp.initFn)
+	if err != nil {
+		// error returned from init function wrapped to be recovered
+		// when component initialized on demand within other init function
+		if !recoverable(err) {
+			err = fmt.Errorf("%w: %s: %w", ErrInitComponent, comp, err)
+		}
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// callInit calls init function. di errors raised with Get within init function
+// are recovered and returned as error. Other panics are propagated
+func callInit(c *Container, initFn func(*Container) (any, error)) (val any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = withStack(toError(r))
+			if !recoverable(err) {
+				panic(err)
+			}
+		}
+	}()
+
+	return initFn(c)
+}
+
+func errCycle(path []coordinate) error {
+	chain := make([]string, 0, len(path))
+	for _, coord := range path {
+		chain = append(chain, coord.String())
+	}
+
+	return fmt.Errorf("%w: %s", ErrCycle, strings.Join(chain, " -> "))
+}
+
+// dependsOn records dependency of component being initialized on requested one
+func (c *Container) dependsOn(coord coordinate) {
+	if len(c.path) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	from := c.path[len(c.path)-1]
+	if slices.Contains(c.deps[from], coord) {
+		return
+	}
+
+	c.deps[from] = append(c.deps[from], coord)
 }
@@ -1,9 +1,13 @@
 package di
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -41,14 +45,61 @@ func Test_Init(t *testing.T) {
 			wantInitErr: ErrNotFound,
 		},
 		{
-			name: "error disordered setup",
+			name: "error component depends on itself",
 			setup: func() (*Container, error) {
 				c := NewContainer()
-				err := Setup[initTestType2](c,
+				err := Setup[initTestType](c,
+					Init(func(c *Container) initTestType {
+						return Get[initTestType](c)
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			wantInitErr: ErrCycle,
+			wantErrContains: []string{
+				"(di.initTestType, (Unnamed)) -> (di.initTestType, (Unnamed))",
+			},
+		},
+		{
+			name: "error deprecated ErrDisordered matches dependency cycle",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[initTestType](c,
+					Init(func(c *Container) initTestType {
+						return Get[initTestType](c)
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			wantInitErr: ErrDisordered,
+		},
+		{
+			name: "error dependency cycle",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*initTestType](c,
+					Init(func(c *Container) *initTestType {
+						Get[initTestType2](c, Name("B"))
+						return &initTestType{}
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[initTestType2](c,
+					Name("B"),
 					Init(func(c *Container) initTestType2 {
-						return initTestType2{
-							itt: Get[initTestType](c),
-						}
+						Get[initTestType](c)
+						return initTestType2{}
 					}),
 				)
 				if err != nil {
@@ -57,6 +108,7 @@ func Test_Init(t *testing.T) {
 
 				err = Setup[initTestType](c,
 					Init(func(c *Container) initTestType {
+						Get[*initTestType](c)
 						return initTestType{}
 					}),
 				)
@@ -66,9 +118,10 @@ func Test_Init(t *testing.T) {
 
 				return c, nil
 			},
-			wantInitErr: ErrDisordered,
+			wantInitErr: ErrCycle,
 			wantErrContains: []string{
-				"(di.initTestType, (Unnamed)) must be set before parent component",
+				"(*di.initTestType, (Unnamed)) -> (di.initTestType2, B) -> (di.initTestType, (Unnamed)) -> (*di.initTestType, (Unnamed))",
+				"di/init_test.go:",
 			},
 		},
 		{
@@ -84,6 +137,33 @@ func Test_Init(t *testing.T) {
 			},
 			wantInitErr: ErrInitialized,
 		},
+		{
+			name: "ok dependency set after dependent",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[initTestType2](c,
+					Init(func(c *Container) initTestType2 {
+						return initTestType2{
+							itt: Get[initTestType](c),
+						}
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[initTestType](c,
+					Init(func(c *Container) initTestType {
+						return initTestType{}
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+		},
 		{
 			name: "ok",
 			setup: func() (*Container, error) {
@@ -154,3 +234,269 @@ func Test_user_panic_in_init_not_recovered(t *testing.T) {
 
 	require.True(t, strings.HasPrefix(panic, "some panic"))
 }
+
+func Test_init_called_once_when_requested_on_demand(t *testing.T) {
+	var (
+		c     = NewContainer()
+		calls = 0
+	)
+
+	err := Setup[initTestType2](c,
+		Init(func(c *Container) initTestType2 {
+			return initTestType2{itt: Get[initTestType](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[initTestType](c,
+		Init(func(c *Container) initTestType {
+			calls++
+			return initTestType{}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func Test_error_returned_from_init_function_of_dependency(t *testing.T) {
+	var (
+		c      = NewContainer()
+		ittErr = errors.New("init error")
+	)
+
+	err := Setup[initTestType2](c,
+		Init(func(c *Container) initTestType2 {
+			return initTestType2{itt: Get[initTestType](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[initTestType](c,
+		InitE(func(c *Container) (initTestType, error) {
+			return initTestType{}, ittErr
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.ErrorIs(t, err, ittErr)
+	require.ErrorIs(t, err, ErrInitComponent)
+	require.Contains(t, err.Error(), "init component: (di.initTestType, (Unnamed)): init error")
+}
+
+func Test_lazy_initialized_on_first_get(t *testing.T) {
+	var (
+		c     = NewContainer()
+		calls atomic.Int32
+	)
+
+	err := Setup[*initTestType](c,
+		Lazy(),
+		Init(func(c *Container) *initTestType {
+			calls.Add(1)
+			return &initTestType{}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+	require.Equal(t, int32(0), calls.Load())
+
+	var (
+		wg   sync.WaitGroup
+		vals = make([]*initTestType, 10)
+	)
+	for i := range vals {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vals[i] = Get[*initTestType](c)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+	for _, v := range vals {
+		require.Same(t, vals[0], v)
+	}
+}
+
+func Test_lazy_initialized_on_init_when_requested(t *testing.T) {
+	var (
+		c     = NewContainer()
+		calls = 0
+	)
+
+	err := Setup[initTestType](c,
+		Lazy(),
+		Init(func(c *Container) initTestType {
+			calls++
+			return initTestType{}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[initTestType2](c,
+		Init(func(c *Container) initTestType2 {
+			return initTestType2{itt: Get[initTestType](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func Test_lazy_error(t *testing.T) {
+	var (
+		c      = NewContainer()
+		calls  = 0
+		ittErr = errors.New("init error")
+	)
+
+	err := Setup[initTestType](c,
+		Lazy(),
+		InitE(func(c *Container) (initTestType, error) {
+			calls++
+			return initTestType{}, ittErr
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[initTestType2](c,
+		Lazy(),
+		Init(func(c *Container) initTestType2 {
+			return initTestType2{itt: Get[initTestType](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	_, err = GetE[initTestType2](c)
+	require.ErrorIs(t, err, ittErr)
+
+	_, err = GetE[initTestType](c)
+	require.ErrorIs(t, err, ittErr)
+	require.Equal(t, 1, calls)
+}
+
+type initTestCycleA struct{}
+type initTestCycleB struct{}
+
+func Test_cycle_initialized_concurrently(t *testing.T) {
+	tests := []struct {
+		name   string
+		opt    setupOpt[any]
+		scoped bool
+	}{
+		{name: "lazy", opt: Lazy()},
+		{name: "scoped", opt: Scoped(), scoped: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				c       = NewContainer()
+				entered sync.WaitGroup
+			)
+
+			// both init functions entered before requesting each other
+			entered.Add(2)
+
+			err := Setup[*initTestCycleA](c,
+				tt.opt,
+				Init(func(c *Container) *initTestCycleA {
+					entered.Done()
+					entered.Wait()
+					Get[*initTestCycleB](c)
+					return &initTestCycleA{}
+				}),
+			)
+			require.NoError(t, err)
+
+			err = Setup[*initTestCycleB](c,
+				tt.opt,
+				Init(func(c *Container) *initTestCycleB {
+					entered.Done()
+					entered.Wait()
+					Get[*initTestCycleA](c)
+					return &initTestCycleB{}
+				}),
+			)
+			require.NoError(t, err)
+			require.NoError(t, c.Init())
+
+			get := c
+			if tt.scoped {
+				get = c.NewScope().Container
+			}
+
+			errs := make(chan error, 2)
+			go func() {
+				_, err := GetE[*initTestCycleA](get)
+				errs <- err
+			}()
+			go func() {
+				_, err := GetE[*initTestCycleB](get)
+				errs <- err
+			}()
+
+			for i := 0; i < 2; i++ {
+				select {
+				case err := <-errs:
+					require.ErrorIs(t, err, ErrCycle)
+					require.Regexp(t, `dependency cycle: \(\*di\.initTestCycle(A|B), \(Unnamed\)\) -> .+ -> \(\*di\.initTestCycle(A|B)`, err.Error())
+				case <-time.After(time.Second):
+					t.Fatal("components initialized concurrently wait for each other")
+				}
+			}
+		})
+	}
+}
+
+type initTestTransient struct{ n int }
+
+func Test_transient_initialized_on_every_get(t *testing.T) {
+	var (
+		c     = NewContainer()
+		calls = 0
+	)
+
+	err := Setup[*initTestTransient](c,
+		Transient(),
+		Init(func(c *Container) *initTestTransient {
+			calls++
+			return &initTestTransient{n: calls}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[initTestType2](c,
+		Init(func(c *Container) initTestType2 {
+			Get[*initTestTransient](c)
+			Get[*initTestTransient](c)
+			return initTestType2{}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	a, err := GetE[*initTestTransient](c)
+	require.NoError(t, err)
+
+	b, err := GetE[*initTestTransient](c)
+	require.NoError(t, err)
+
+	require.NotSame(t, a, b)
+	require.Equal(t, 4, calls)
+}
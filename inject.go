@@ -0,0 +1,124 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// InitStruct defines init function creating struct of type T with fields
+// injected with Inject. T should be struct or pointer to struct
+func InitStruct[T any]() withInitE[T] {
+	return func(c *Container) (T, error) {
+		var t T
+
+		type_ := reflect.TypeOf(&t).Elem()
+		if type_.Kind() != reflect.Pointer {
+			return t, Inject(c, &t)
+		}
+
+		if type_.Elem().Kind() != reflect.Struct {
+			return t, fmt.Errorf("%w: %s is not pointer to struct", ErrInject, type_)
+		}
+
+		t = reflect.New(type_.Elem()).Interface().(T)
+		return t, Inject(c, t)
+	}
+}
+
+// Inject sets exported fields of struct pointed by ptr with components
+// requested from container by field type. Embedded fields injected by type too,
+// fields of embedded struct with tag di:"inline" injected instead.
+// Field tag di:"name=primary,optional" defines component name and makes field
+// not set if component not found, field of type di.Optional is not required too.
+// Field with tag di:"-" is skipped.
+// Returns errors of all the fields not injected
+func Inject(c *Container, ptr any) error {
+	if err := c.checkGet(); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not pointer to struct", ErrInject, ptr)
+	}
+
+	return errors.Join(c.injectFields(v.Elem(), v.Elem().Type().Name())...)
+}
+
+func (c *Container) injectFields(v reflect.Value, path string) []error {
+	var errs []error
+
+	for i := 0; i < v.NumField(); i++ {
+		var (
+			field = v.Type().Field(i)
+			tag   = field.Tag.Get("di")
+			fpath = path + "." + field.Name
+		)
+
+		if tag == "-" {
+			continue
+		}
+
+		// fields of embedded struct are promoted so set even if struct type is unexported
+		if tag == "inline" {
+			if !field.Anonymous || field.Type.Kind() != reflect.Struct {
+				errs = append(errs, fmt.Errorf("%w: field %s: inline field is not embedded struct", ErrInject, fpath))
+				continue
+			}
+
+			errs = append(errs, c.injectFields(v.Field(i), fpath)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if err := c.injectField(v.Field(i), tag); err != nil {
+			errs = append(errs, fmt.Errorf("%w: field %s: %w", ErrInject, fpath, err))
+		}
+	}
+
+	return errs
+}
+
+func (c *Container) injectField(v reflect.Value, tag string) error {
+	var (
+		coord    = coordinate{type_: v.Type()}
+		optional = false
+	)
+
+	if tag != "" {
+		for _, opt := range strings.Split(tag, ",") {
+			switch {
+			case strings.HasPrefix(opt, "name="):
+				coord.name = strings.TrimPrefix(opt, "name=")
+			case opt == "optional":
+				optional = true
+			default:
+				return fmt.Errorf("unknown tag option %q", opt)
+			}
+		}
+	}
+
+	// errors of found component returned even if field is optional
+	if optional && !isOptional(coord.type_) {
+		if _, _, ok := c.lookup(coord); !ok {
+			return nil
+		}
+	}
+
+	val, err := c.getArg(coord.type_, coord.name)
+	if err != nil {
+		return err
+	}
+
+	// nil value of interface type keeps field zero
+	if val != nil {
+		v.Set(reflect.ValueOf(val))
+	}
+
+	return nil
+}
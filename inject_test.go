@@ -0,0 +1,119 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type injectTestDB struct{ name string }
+type injectTestCache struct{}
+type injectTestLogger interface{ Log(string) }
+
+type injectTestBase struct {
+	Logger injectTestLogger `di:"optional"`
+}
+
+type injectTestHandler struct {
+	injectTestBase `di:"inline"`
+
+	Primary *injectTestDB `di:"name=primary"`
+	Replica *injectTestDB `di:"name=replica,optional"`
+	Cache   *injectTestCache
+	Skipped *injectTestCache `di:"-"`
+
+	notInjected *injectTestCache
+}
+
+// InjectTestConfig is exported to be embedded and injected by type
+type InjectTestConfig struct{ Env string }
+
+func Test_Inject_embedded(t *testing.T) {
+	c := NewContainer()
+	require.NoError(t, Value(c, InjectTestConfig{Env: "test"}))
+	require.NoError(t, Value(c, &injectTestDB{name: "primary"}, Name("primary")))
+	require.NoError(t, c.Init())
+
+	var s struct {
+		InjectTestConfig
+		DB *injectTestDB `di:"name=primary"`
+	}
+	require.NoError(t, Inject(c, &s))
+	require.Equal(t, "test", s.Env)
+	require.Equal(t, "primary", s.DB.name)
+
+	var inline struct {
+		InjectTestConfig `di:"inline"`
+	}
+	err := Inject(c, &inline)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Contains(t, err.Error(), "InjectTestConfig.Env")
+
+	var notEmbedded struct {
+		Config InjectTestConfig `di:"inline"`
+	}
+	err = Inject(c, &notEmbedded)
+	require.ErrorIs(t, err, ErrInject)
+	require.Contains(t, err.Error(), "inline field is not embedded struct")
+}
+
+func Test_Inject(t *testing.T) {
+	c := NewContainer()
+	require.NoError(t, Value(c, &injectTestDB{name: "primary"}, Name("primary")))
+	require.NoError(t, Value(c, &injectTestCache{}))
+	require.NoError(t, c.Init())
+
+	var h injectTestHandler
+	require.NoError(t, Inject(c, &h))
+
+	require.Equal(t, "primary", h.Primary.name)
+	require.Nil(t, h.Replica)
+	require.Nil(t, h.Logger)
+	require.Same(t, Get[*injectTestCache](c), h.Cache)
+	require.Nil(t, h.Skipped)
+	require.Nil(t, h.notInjected)
+}
+
+func Test_InitStruct(t *testing.T) {
+	c := NewContainer()
+	require.NoError(t, Value(c, &injectTestDB{name: "primary"}, Name("primary")))
+	require.NoError(t, Value(c, &injectTestCache{}))
+
+	require.NoError(t, Setup[*injectTestHandler](c, InitStruct[*injectTestHandler]()))
+	require.NoError(t, Setup[injectTestHandler](c, InitStruct[injectTestHandler]()))
+	require.NoError(t, c.Init())
+
+	require.Equal(t, "primary", Get[*injectTestHandler](c).Primary.name)
+	require.Equal(t, "primary", Get[injectTestHandler](c).Primary.name)
+
+	// dependencies recorded
+	require.Len(t, c.Graph().Edges, 4)
+}
+
+func Test_Inject_errors(t *testing.T) {
+	c := NewContainer()
+	require.NoError(t, c.Init())
+
+	var h injectTestHandler
+	err := Inject(c, &h)
+	require.ErrorIs(t, err, ErrInject)
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Contains(t, err.Error(), "field injectTestHandler.Primary")
+	require.Contains(t, err.Error(), "field injectTestHandler.Cache")
+	require.NotContains(t, err.Error(), "Replica")
+	require.NotContains(t, err.Error(), "Logger")
+
+	err = Inject(c, h)
+	require.ErrorIs(t, err, ErrInject)
+
+	var s struct {
+		DB *injectTestDB `di:"primary"`
+	}
+	err = Inject(c, &s)
+	require.ErrorIs(t, err, ErrInject)
+	require.Contains(t, err.Error(), `unknown tag option "primary"`)
+
+	c = NewContainer()
+	require.NoError(t, Setup[*int](c, InitStruct[*int]()))
+	require.ErrorIs(t, c.Init(), ErrInject)
+}
@@ -0,0 +1,149 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// OnStart defines function called on Start. Functions called one by one
+// in components initialization order so component started after it's dependencies
+func OnStart[T any](fn func(context.Context, T) error) withOnStart[T] { return fn }
+
+// OnStop defines function called on Stop. Functions called one by one
+// in reverse components initialization order so component stopped before it's dependencies
+func OnStop[T any](fn func(context.Context, T) error) withOnStop[T] { return fn }
+
+func (c *Container) enterStart() ([]*component, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	if c.running {
+		return nil, ErrStarted
+	}
+
+	c.running = true
+
+	var comps []*component
+	for _, coord := range c.resolved {
+		comp := c.components[coord]
+		if comp.onStart != nil || comp.onStop != nil {
+			comps = append(comps, comp)
+		}
+	}
+
+	return comps, nil
+}
+
+func (c *Container) markStarted(comp *component) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.started = append(c.started, comp)
+}
+
+// Start calls functions defined with OnStart. Start stops on first error
+// and stops components started before error with Stop
+func (c *Container) Start(ctx context.Context) error {
+	if err := c.checkNotScope(); err != nil {
+		return err
+	}
+
+	comps, err := c.enterStart()
+	if err != nil {
+		return err
+	}
+
+	for _, comp := range comps {
+		if comp.onStart != nil {
+			if err = comp.onStart(ctx, comp.val); err != nil {
+				err = fmt.Errorf("%w: %s: %w", ErrStart, comp, err)
+				// rollback components started before error. Components are not cleaned up
+				// as container may be started again
+				return errors.Join(err, c.stop(context.WithoutCancel(ctx), false))
+			}
+		}
+
+		// component without OnStart considered started
+		c.markStarted(comp)
+	}
+
+	return nil
+}
+
+// stopping is component stopped with Stop
+type stopping struct {
+	comp    *component
+	started bool
+	cleanup func()
+}
+
+// exitStart returns started components and components with cleanup if cleanup is true
+func (c *Container) exitStart(cleanup bool) []stopping {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	started := make(map[*component]bool, len(c.started))
+	for _, comp := range c.started {
+		started[comp] = true
+	}
+
+	// components in order they were initialized so cleanup
+	// of component called after it's dependents stopped
+	var comps []stopping
+	for _, coord := range c.resolved {
+		comp := c.components[coord]
+		s := stopping{comp: comp, started: started[comp]}
+		if cleanup {
+			// cleanup called once
+			s.cleanup, comp.cleanup = comp.cleanup, nil
+		}
+
+		if s.started || s.cleanup != nil {
+			comps = append(comps, s)
+		}
+	}
+
+	c.started = nil
+	c.running = false
+
+	return comps
+}
+
+// Stop calls functions defined with OnStop for components started with Start
+// in reverse order. Cleanup functions returned from constructors set with
+// di.Provide called whether or not Start called. Stop continues on error
+// and returns all the errors occurred
+func (c *Container) Stop(ctx context.Context) error {
+	if err := c.checkNotScope(); err != nil {
+		return err
+	}
+
+	return c.stop(ctx, true)
+}
+
+func (c *Container) stop(ctx context.Context, cleanup bool) error {
+	var (
+		comps = c.exitStart(cleanup)
+		errs  []error
+	)
+
+	for i := len(comps) - 1; i >= 0; i-- {
+		s := comps[i]
+		if s.started && s.comp.onStop != nil {
+			if err := s.comp.onStop(ctx, s.comp.val); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %w", ErrStop, s.comp, err))
+			}
+		}
+
+		if s.cleanup != nil {
+			s.cleanup()
+		}
+	}
+
+	return errors.Join(errs...)
+}
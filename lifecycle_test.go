@@ -0,0 +1,234 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lifecycleTestDB struct{}
+type lifecycleTestCache struct{}
+type lifecycleTestServer struct{}
+
+func Test_Start_Stop(t *testing.T) {
+	var (
+		startErr = errors.New("start error")
+		stopErr  = errors.New("stop error")
+	)
+
+	tests := []struct {
+		name            string
+		setup           func(calls *[]string) (*Container, error)
+		wantStartErr    error
+		wantStopErr     error
+		wantErrContains []string
+		want            []string
+	}{
+		{
+			name: "started in init order and stopped in reverse order",
+			setup: func(calls *[]string) (*Container, error) {
+				c := NewContainer()
+				err := Setup[*lifecycleTestServer](c,
+					Init(func(c *Container) *lifecycleTestServer {
+						Get[*lifecycleTestDB](c)
+						return &lifecycleTestServer{}
+					}),
+					OnStart(func(ctx context.Context, s *lifecycleTestServer) error {
+						*calls = append(*calls, "start server")
+						return nil
+					}),
+					OnStop(func(ctx context.Context, s *lifecycleTestServer) error {
+						*calls = append(*calls, "stop server")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*lifecycleTestDB](c,
+					Init(func(c *Container) *lifecycleTestDB { return &lifecycleTestDB{} }),
+					OnStart(func(ctx context.Context, s *lifecycleTestDB) error {
+						*calls = append(*calls, "start db")
+						return nil
+					}),
+					OnStop(func(ctx context.Context, s *lifecycleTestDB) error {
+						*calls = append(*calls, "stop db")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			want: []string{"start db", "start server", "stop server", "stop db"},
+		},
+		{
+			name: "error start stops started",
+			setup: func(calls *[]string) (*Container, error) {
+				c := NewContainer()
+				err := Setup[*lifecycleTestServer](c,
+					Init(func(c *Container) *lifecycleTestServer {
+						Get[*lifecycleTestCache](c)
+						return &lifecycleTestServer{}
+					}),
+					OnStart(func(ctx context.Context, s *lifecycleTestServer) error {
+						*calls = append(*calls, "start server")
+						return startErr
+					}),
+					OnStop(func(ctx context.Context, s *lifecycleTestServer) error {
+						*calls = append(*calls, "stop server")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				// component without OnStart considered started
+				err = Setup[*lifecycleTestCache](c,
+					Init(func(c *Container) *lifecycleTestCache {
+						Get[*lifecycleTestDB](c)
+						return &lifecycleTestCache{}
+					}),
+					OnStop(func(ctx context.Context, s *lifecycleTestCache) error {
+						*calls = append(*calls, "stop cache")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*lifecycleTestDB](c,
+					Init(func(c *Container) *lifecycleTestDB { return &lifecycleTestDB{} }),
+					OnStart(func(ctx context.Context, s *lifecycleTestDB) error {
+						*calls = append(*calls, "start db")
+						return nil
+					}),
+					OnStop(func(ctx context.Context, s *lifecycleTestDB) error {
+						*calls = append(*calls, "stop db")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			wantStartErr:    startErr,
+			wantErrContains: []string{"(*di.lifecycleTestServer, (Unnamed))"},
+			// already stopped components not stopped on Stop
+			want: []string{"start db", "start server", "stop cache", "stop db"},
+		},
+		{
+			name: "error stop continues stopping",
+			setup: func(calls *[]string) (*Container, error) {
+				c := NewContainer()
+				err := Setup[*lifecycleTestServer](c,
+					Init(func(c *Container) *lifecycleTestServer {
+						Get[*lifecycleTestCache](c)
+						return &lifecycleTestServer{}
+					}),
+					OnStop(func(ctx context.Context, s *lifecycleTestServer) error {
+						*calls = append(*calls, "stop server")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*lifecycleTestCache](c,
+					Init(func(c *Container) *lifecycleTestCache {
+						Get[*lifecycleTestDB](c)
+						return &lifecycleTestCache{}
+					}),
+					OnStop(func(ctx context.Context, s *lifecycleTestCache) error {
+						*calls = append(*calls, "stop cache")
+						return stopErr
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*lifecycleTestDB](c,
+					Init(func(c *Container) *lifecycleTestDB { return &lifecycleTestDB{} }),
+					OnStop(func(ctx context.Context, s *lifecycleTestDB) error {
+						*calls = append(*calls, "stop db")
+						return stopErr
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			wantStopErr: stopErr,
+			wantErrContains: []string{
+				"(*di.lifecycleTestCache, (Unnamed))",
+				"(*di.lifecycleTestDB, (Unnamed))",
+			},
+			want: []string{"stop server", "stop cache", "stop db"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+
+			c, err := tt.setup(&calls)
+			require.NoError(t, err)
+			require.NoError(t, c.Init())
+
+			err = c.Start(context.Background())
+			if tt.wantStartErr != nil {
+				require.ErrorIs(t, err, tt.wantStartErr)
+				require.ErrorIs(t, err, ErrStart)
+				for _, str := range tt.wantErrContains {
+					require.Contains(t, err.Error(), str)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+
+			err = c.Stop(context.Background())
+			if tt.wantStopErr != nil {
+				require.ErrorIs(t, err, tt.wantStopErr)
+				require.ErrorIs(t, err, ErrStop)
+				for _, str := range tt.wantErrContains {
+					require.Contains(t, err.Error(), str)
+				}
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.want, calls)
+		})
+	}
+}
+
+func Test_error_start_started(t *testing.T) {
+	c := NewContainer()
+	require.NoError(t, Value(c, &lifecycleTestDB{}))
+	require.NoError(t, c.Init())
+
+	err := c.Start(context.Background())
+	require.NoError(t, err)
+
+	err = c.Start(context.Background())
+	require.ErrorIs(t, err, ErrStarted)
+}
+
+func Test_error_start_before_init(t *testing.T) {
+	c := NewContainer()
+	err := c.Start(context.Background())
+	require.ErrorIs(t, err, ErrNotInitialized)
+}
@@ -0,0 +1,48 @@
+package di
+
+import (
+	"reflect"
+)
+
+// GetMap returns components of type T by names. Components
+// made retrievable as T with di.As are included. Scoped components
+// returned only if container is scope. Container components shadow
+// parent components with same name
+func GetMap[T any](c *Container) (map[string]T, error) {
+	if err := c.checkGet(); err != nil {
+		return nil, err
+	}
+
+	var (
+		t     T
+		type_ = reflect.TypeOf(&t).Elem()
+		ts    = make(map[string]T)
+	)
+
+	// components initialized in order corresponding di.Setup were called
+	for _, owner := range c.chain() {
+		for _, coord := range owner.initOrder {
+			comp := owner.components[coord]
+			if _, found, _ := c.lookup(coordinate{type_: type_, name: coord.name}); found != comp || !c.visible(owner, comp) {
+				continue
+			}
+
+			val, err := c.resolveAt(owner, comp)
+			if err != nil {
+				return nil, err
+			}
+
+			ts[coord.name] = as[T](val)
+		}
+	}
+
+	return ts, nil
+}
+
+// Map returns components of type T by names. Use it within init function,
+// on error panic occurs which will be captured within Init function. See GetMap
+func Map[T any](c *Container) map[string]T {
+	ts, err := GetMap[T](c)
+	must(err)
+	return ts
+}
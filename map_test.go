@@ -0,0 +1,75 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mapTestPool struct{ tenant string }
+type mapTestRouter struct{ pools map[string]*mapTestPool }
+
+type mapTestClient interface{ region() string }
+type mapTestRegionClient struct{ r string }
+
+func (c *mapTestRegionClient) region() string { return c.r }
+
+func Test_Map(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[*mapTestRouter](c,
+		Init(func(c *Container) *mapTestRouter {
+			return &mapTestRouter{pools: Map[*mapTestPool](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	for _, tenant := range []string{"acme", "globex"} {
+		tenant := tenant
+		err = Setup[*mapTestPool](c,
+			Name(tenant),
+			Init(func(c *Container) *mapTestPool { return &mapTestPool{tenant: tenant} }),
+		)
+		require.NoError(t, err)
+	}
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	r, err := GetE[*mapTestRouter](c)
+	require.NoError(t, err)
+	require.Equal(t, map[string]*mapTestPool{
+		"acme":   {tenant: "acme"},
+		"globex": {tenant: "globex"},
+	}, r.pools)
+}
+
+func Test_GetMap_as(t *testing.T) {
+	c := NewContainer()
+
+	for _, region := range []string{"eu", "us"} {
+		region := region
+		err := Setup[*mapTestRegionClient](c,
+			Name(region),
+			Init(func(c *Container) *mapTestRegionClient { return &mapTestRegionClient{r: region} }),
+			As[mapTestClient](),
+		)
+		require.NoError(t, err)
+	}
+
+	err := c.Init()
+	require.NoError(t, err)
+
+	clients, err := GetMap[mapTestClient](c)
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+	require.Equal(t, "eu", clients["eu"].region())
+	require.Equal(t, "us", clients["us"].region())
+}
+
+func Test_GetMap_before_init(t *testing.T) {
+	c := NewContainer()
+
+	_, err := GetMap[*mapTestPool](c)
+	require.ErrorIs(t, err, ErrNotInitialized)
+}
@@ -0,0 +1,73 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// Module is named set of components. Module name recorded on components
+// set within module setup functions and shown in errors and graph
+type Module struct {
+	Name string
+	// Setups are functions setting module components
+	Setups []func(*Container) error
+	// Modules are modules installed before module setup functions called
+	Modules []Module
+}
+
+// Install installs module with it's nested modules. Module can be installed once,
+// nested module installed before is skipped so modules can share dependencies
+func (c *Container) Install(m Module) error {
+	if err := c.markInstalled(m); err != nil {
+		return err
+	}
+
+	return c.install(m)
+}
+
+func (c *Container) markInstalled(m Module) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkSetup(); err != nil {
+		return err
+	}
+
+	if m.Name == "" {
+		return fmt.Errorf("%w: %s", ErrModuleNameNotSet, debug.Stack())
+	}
+
+	if c.modules[m.Name] {
+		return fmt.Errorf("%w: %s: %s", ErrModuleInstalled, m.Name, debug.Stack())
+	}
+
+	c.modules[m.Name] = true
+
+	return nil
+}
+
+func (c *Container) install(m Module) error {
+	for _, nested := range m.Modules {
+		err := c.markInstalled(nested)
+		if errors.Is(err, ErrModuleInstalled) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = c.install(nested); err != nil {
+			return err
+		}
+	}
+
+	view := &Container{state: c.state, module: m.Name}
+	for _, setup := range m.Setups {
+		if err := setup(view); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type moduleTestConfig struct{}
+type moduleTestDB struct{}
+type moduleTestServer struct{}
+
+var moduleTestConfigModule = Module{
+	Name: "config",
+	Setups: []func(*Container) error{
+		func(c *Container) error { return Value(c, &moduleTestConfig{}) },
+	},
+}
+
+var moduleTestPostgres = Module{
+	Name:    "postgres",
+	Modules: []Module{moduleTestConfigModule},
+	Setups: []func(*Container) error{
+		func(c *Container) error {
+			return Setup[*moduleTestDB](c,
+				Init(func(c *Container) *moduleTestDB {
+					Get[*moduleTestConfig](c)
+					return &moduleTestDB{}
+				}),
+			)
+		},
+	},
+}
+
+var moduleTestHTTP = Module{
+	Name:    "http",
+	Modules: []Module{moduleTestConfigModule},
+	Setups: []func(*Container) error{
+		func(c *Container) error {
+			return Setup[*moduleTestServer](c,
+				Init(func(c *Container) *moduleTestServer {
+					Get[*moduleTestDB](c)
+					return &moduleTestServer{}
+				}),
+				OnStart(func(ctx context.Context, s *moduleTestServer) error { return errors.New("listen") }),
+			)
+		},
+	},
+}
+
+func Test_Install(t *testing.T) {
+	c := NewContainer()
+
+	require.NoError(t, c.Install(moduleTestPostgres))
+	// shared nested module installed once
+	require.NoError(t, c.Install(moduleTestHTTP))
+	require.NoError(t, c.Init())
+
+	g := c.Graph()
+	require.Len(t, g.Nodes, 3)
+	for i, module := range []string{"config", "postgres", "http"} {
+		require.Equal(t, module, g.Nodes[i].Module)
+	}
+
+	var b strings.Builder
+	require.NoError(t, c.WriteDOT(&b))
+	require.Contains(t, b.String(), `label="*di.moduleTestServer\nmodule: http\nstages: on start"`)
+
+	err := c.Start(context.Background())
+	require.ErrorIs(t, err, ErrStart)
+	require.Contains(t, err.Error(), "(*di.moduleTestServer, (Unnamed)) in module http: listen")
+}
+
+func Test_Install_errors(t *testing.T) {
+	c := NewContainer()
+
+	require.NoError(t, c.Install(moduleTestConfigModule))
+
+	err := c.Install(moduleTestConfigModule)
+	require.ErrorIs(t, err, ErrModuleInstalled)
+
+	err = c.Install(Module{Setups: moduleTestConfigModule.Setups})
+	require.ErrorIs(t, err, ErrModuleNameNotSet)
+
+	err = c.Install(Module{Name: "config2", Setups: moduleTestConfigModule.Setups})
+	require.ErrorIs(t, err, ErrComponentSet)
+	require.Contains(t, err.Error(), "(*di.moduleTestConfig, (Unnamed)) in module config")
+
+	require.NoError(t, c.Init())
+	err = c.Install(Module{Name: "late"})
+	require.ErrorIs(t, err, ErrInitialized)
+}
+
+type moduleTestConnConfig struct{}
+type moduleTestPool struct{}
+
+func Test_Private(t *testing.T) {
+	c := NewContainer()
+
+	err := c.Install(Module{
+		Name: "postgres",
+		Setups: []func(*Container) error{
+			func(c *Container) error { return Value(c, &moduleTestConnConfig{}, Private()) },
+			func(c *Container) error {
+				return Setup[*moduleTestPool](c,
+					Init(func(c *Container) *moduleTestPool {
+						Get[*moduleTestConnConfig](c)
+						return &moduleTestPool{}
+					}),
+				)
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	err = c.Install(Module{
+		Name: "http",
+		Setups: []func(*Container) error{
+			func(c *Container) error {
+				return Setup[*moduleTestServer](c,
+					Init(func(c *Container) *moduleTestServer {
+						Get[*moduleTestConnConfig](c)
+						return &moduleTestServer{}
+					}),
+					Lazy(),
+				)
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	_, err = GetE[*moduleTestPool](c)
+	require.NoError(t, err)
+
+	_, err = GetE[*moduleTestConnConfig](c)
+	require.ErrorIs(t, err, ErrNotVisible)
+	require.Contains(t, err.Error(), "(*di.moduleTestConnConfig, (Unnamed)) in module postgres outside of module")
+
+	_, err = GetE[*moduleTestServer](c)
+	require.ErrorIs(t, err, ErrNotVisible)
+	require.Contains(t, err.Error(), "in module postgres from module http")
+
+	require.Empty(t, All[*moduleTestConnConfig](c))
+	require.Empty(t, Map[*moduleTestConnConfig](c))
+}
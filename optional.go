@@ -0,0 +1,84 @@
+package di
+
+import (
+	"reflect"
+)
+
+// GetOptional returns component and true if component set or zero value
+// and false otherwise. Intended for init functions only: on errors other
+// than component not set panic occurs which will be captured within Init
+// function. Use GetOptionalE outside of init functions. See Get
+func GetOptional[T any](c *Container, opts ...getOpt[T]) (T, bool) {
+	t, ok, err := GetOptionalE(c, opts...)
+	must(err)
+	return t, ok
+}
+
+// GetOptionalE is GetOptional returning errors other than component not set
+// including ErrNotInitialized and errors of lazy component initialization
+func GetOptionalE[T any](c *Container, opts ...getOpt[T]) (T, bool, error) {
+	var (
+		t T
+	)
+
+	if err := c.checkGet(); err != nil {
+		return t, false, err
+	}
+
+	name, err := processGetOpts(opts...)
+	if err != nil {
+		return t, false, err
+	}
+
+	return getOptional[T](c, name)
+}
+
+// getOptional returns component if set. Errors of component set are returned
+func getOptional[T any](c *Container, name string) (T, bool, error) {
+	var (
+		t T
+	)
+
+	coord := coordinate{
+		type_: reflect.TypeOf(&t).Elem(),
+		name:  name,
+	}
+
+	if _, _, ok := c.lookup(coord); !ok {
+		return t, false, nil
+	}
+
+	val, err := c.get(coord)
+	if err != nil {
+		return t, false, err
+	}
+
+	return as[T](val), true, nil
+}
+
+// Optional is component which may be not set. Use it as constructor
+// parameter with di.Provide or as struct field with di.Inject
+type Optional[T any] struct {
+	Value T
+	// Ok is true if component set
+	Ok bool
+}
+
+// optional is implemented by Optional of any type
+type optional interface {
+	getOptional(c *Container, name string) (any, error)
+}
+
+func isOptional(type_ reflect.Type) bool {
+	_, ok := reflect.Zero(type_).Interface().(optional)
+	return ok
+}
+
+func (Optional[T]) getOptional(c *Container, name string) (any, error) {
+	t, ok, err := getOptional[T](c, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return Optional[T]{Value: t, Ok: ok}, nil
+}
@@ -0,0 +1,105 @@
+package di
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type optionalTestExporter struct{}
+type optionalTestTracer struct{ exporter *optionalTestExporter }
+type optionalTestFailing struct{}
+
+func Test_GetOptional(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[*optionalTestTracer](c,
+		Init(func(c *Container) *optionalTestTracer {
+			exporter, _ := GetOptional[*optionalTestExporter](c)
+			return &optionalTestTracer{exporter: exporter}
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, Value(c, &optionalTestExporter{}, Name("otlp")))
+	require.NoError(t, c.Init())
+
+	require.Nil(t, Get[*optionalTestTracer](c).exporter)
+
+	exporter, ok := GetOptional[*optionalTestExporter](c, Name("otlp"))
+	require.True(t, ok)
+	require.Same(t, Get[*optionalTestExporter](c, Name("otlp")), exporter)
+}
+
+func Test_GetOptional_errors(t *testing.T) {
+	errFail := errors.New("fail")
+
+	c := NewContainer()
+	require.Panics(t, func() { GetOptional[*optionalTestExporter](c) })
+
+	err := Setup[*optionalTestFailing](c,
+		InitE(func(c *Container) (*optionalTestFailing, error) { return nil, errFail }),
+		Lazy(),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*optionalTestTracer](c,
+		Init(func(c *Container) *optionalTestTracer {
+			// error of component set is not considered as component not set
+			GetOptional[*optionalTestFailing](c)
+			return &optionalTestTracer{}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.ErrorIs(t, err, ErrInitComponent)
+	require.ErrorIs(t, err, errFail)
+}
+
+func Test_GetOptionalE(t *testing.T) {
+	errFail := errors.New("fail")
+
+	c := NewContainer()
+
+	_, _, err := GetOptionalE[*optionalTestExporter](c)
+	require.ErrorIs(t, err, ErrNotInitialized)
+
+	err = Setup[*optionalTestFailing](c,
+		InitE(func(c *Container) (*optionalTestFailing, error) { return nil, errFail }),
+		Lazy(),
+	)
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	exporter, ok, err := GetOptionalE[*optionalTestExporter](c)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Nil(t, exporter)
+
+	_, ok, err = GetOptionalE[*optionalTestFailing](c)
+	require.ErrorIs(t, err, errFail)
+	require.False(t, ok)
+}
+
+func Test_Optional(t *testing.T) {
+	c := NewContainer()
+
+	require.NoError(t, Value(c, &optionalTestExporter{}))
+	require.NoError(t, Provide(c, func(e Optional[*optionalTestExporter], f Optional[*optionalTestFailing]) *optionalTestTracer {
+		require.True(t, e.Ok)
+		require.False(t, f.Ok)
+		return &optionalTestTracer{exporter: e.Value}
+	}))
+	require.NoError(t, c.Init())
+
+	require.Same(t, Get[*optionalTestExporter](c), Get[*optionalTestTracer](c).exporter)
+
+	var s struct {
+		Exporter Optional[*optionalTestExporter]
+		Named    Optional[*optionalTestExporter] `di:"name=otlp,optional"`
+	}
+	require.NoError(t, Inject(c, &s))
+	require.True(t, s.Exporter.Ok)
+	require.False(t, s.Named.Ok)
+}
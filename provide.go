@@ -0,0 +1,156 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+)
+
+type provideOpt interface {
+	provideOpt()
+}
+
+func (o withName) provideOpt()     {}
+func (withAs) provideOpt()         {}
+func (withLazy) provideOpt()       {}
+func (withPrivate) provideOpt()    {}
+func (withScope) provideOpt()      {}
+func (withStage[T]) provideOpt()   {}
+func (withOnStart[T]) provideOpt() {}
+func (withOnStop[T]) provideOpt()  {}
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	cleanupType = reflect.TypeOf((func())(nil))
+)
+
+// setCleanup keeps cleanup function returned from constructor.
+// Cleanup of scoped component kept by scope instance
+func (c *Container) setCleanup(comp *component, cleanup func()) {
+	if comp.scope == scopeScoped {
+		c.scope.setCleanup(comp, cleanup)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	comp.cleanup = cleanup
+}
+
+// getArg returns component requested by constructor parameter or struct field type
+func (c *Container) getArg(type_ reflect.Type, name string) (any, error) {
+	if opt, ok := reflect.Zero(type_).Interface().(optional); ok {
+		return opt.getOptional(c, name)
+	}
+
+	return c.get(coordinate{type_: type_, name: name})
+}
+
+// Provide sets component initialized with constructor function. Constructor
+// parameters are requested from container by type, parameter of type di.Optional
+// is not required to be set. Constructor should return
+// component and optionally error or cleanup function and error: (T), (T, error),
+// (T, func(), error). Cleanup function called on Stop whether or not Start called,
+// cleanup of scoped component called on scope Close. Transient component can not
+// return cleanup.
+// Stages and lifecycle hooks defined for type component is assignable to
+func Provide(c *Container, ctor any, opts ...provideOpt) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkSetup(); err != nil {
+		return err
+	}
+
+	fn := reflect.ValueOf(ctor)
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return fmt.Errorf("%w: %T is not function: %s", ErrConstructor, ctor, debug.Stack())
+	}
+
+	var (
+		type_      = fn.Type()
+		withErr    bool
+		withClean  bool
+		s          = setup{stages: make(map[string]stage)}
+		numResults = type_.NumOut()
+	)
+
+	switch {
+	case numResults == 1:
+	case numResults == 2 && type_.Out(1) == errorType:
+		withErr = true
+	case numResults == 3 && type_.Out(1) == cleanupType && type_.Out(2) == errorType:
+		withErr = true
+		withClean = true
+	default:
+		return fmt.Errorf("%w: %s should return (T), (T, error) or (T, func(), error): %s", ErrConstructor, type_, debug.Stack())
+	}
+
+	if type_.IsVariadic() {
+		return fmt.Errorf("%w: %s is variadic: %s", ErrConstructor, type_, debug.Stack())
+	}
+
+	for _, o := range opts {
+		switch o := o.(type) {
+		case lifecycleOpt:
+			// stage function accepts component of type it is defined for
+			if !type_.Out(0).AssignableTo(o.componentType()) {
+				return fmt.Errorf("%w: %s to %s: %s", ErrNotAssignable, type_.Out(0), o.componentType(), debug.Stack())
+			}
+			if err := o.apply(&s); err != nil {
+				return err
+			}
+		default:
+			if err := s.applyComponentOpt(o); err != nil {
+				return err
+			}
+		}
+	}
+
+	// component set after init function defined to keep cleanup returned from constructor
+	var comp *component
+
+	s.initFn = func(c *Container) (any, error) {
+		args := make([]reflect.Value, type_.NumIn())
+		for i := range args {
+			val, err := c.getArg(type_.In(i), "")
+			if err != nil {
+				return nil, fmt.Errorf("%w: argument %d of %s", err, i, type_)
+			}
+
+			// nil value of interface type has no type so zero value used
+			if val == nil {
+				args[i] = reflect.Zero(type_.In(i))
+			} else {
+				args[i] = reflect.ValueOf(val)
+			}
+		}
+
+		out := fn.Call(args)
+		if withErr {
+			if err, _ := out[len(out)-1].Interface().(error); err != nil {
+				return nil, err
+			}
+		}
+		if withClean {
+			cleanup, _ := out[1].Interface().(func())
+			c.setCleanup(comp, cleanup)
+		}
+
+		return out[0].Interface(), nil
+	}
+
+	// cleanup function kept for singleton or scoped instance
+	if withClean && s.scope == scopeTransient {
+		return fmt.Errorf("%w: for %s type (%s) with cleanup: %s", ErrScopeStage, s.scope, type_.Out(0), debug.Stack())
+	}
+
+	if err := s.check(type_.Out(0)); err != nil {
+		return err
+	}
+
+	comp, err := c.set(type_.Out(0), s)
+
+	return err
+}
@@ -0,0 +1,277 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type provideTestConfig struct{ dsn string }
+type provideTestDB struct{ dsn string }
+type provideTestRepo interface{ DSN() string }
+type provideTestRepoImpl struct{ db *provideTestDB }
+
+func (r *provideTestRepoImpl) DSN() string { return r.db.dsn }
+
+type provideTestService struct {
+	repo   provideTestRepo
+	config *provideTestConfig
+}
+
+func newProvideTestDB(cfg *provideTestConfig) (*provideTestDB, func(), error) {
+	return &provideTestDB{dsn: cfg.dsn}, func() { cfg.dsn = "closed" }, nil
+}
+
+func newProvideTestRepo(db *provideTestDB) (*provideTestRepoImpl, error) {
+	return &provideTestRepoImpl{db: db}, nil
+}
+
+func newProvideTestService(repo provideTestRepo, cfg *provideTestConfig) *provideTestService {
+	return &provideTestService{repo: repo, config: cfg}
+}
+
+func Test_Provide(t *testing.T) {
+	c := NewContainer()
+
+	cfg := &provideTestConfig{dsn: "postgres://"}
+	require.NoError(t, Value(c, cfg))
+	require.NoError(t, Provide(c, newProvideTestService))
+	require.NoError(t, Provide(c, newProvideTestRepo, As[provideTestRepo]()))
+	require.NoError(t, Provide(c, newProvideTestDB))
+	require.NoError(t, c.Init())
+
+	svc := Get[*provideTestService](c)
+	require.Equal(t, "postgres://", svc.repo.DSN())
+	require.Same(t, cfg, svc.config)
+
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+	require.NoError(t, c.Stop(ctx))
+	require.Equal(t, "closed", cfg.dsn)
+}
+
+func Test_Provide_cleanup(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []provideOpt
+		start bool
+		want  []string
+	}{
+		{name: "not started", want: []string{"cleanup db"}},
+		{name: "started", start: true, want: []string{"stop repo", "cleanup db"}},
+		{
+			name:  "lazy initialized after start",
+			opts:  []provideOpt{Lazy()},
+			start: true,
+			// in reverse initialization order
+			want: []string{"cleanup db", "stop repo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				c     = NewContainer()
+				ctx   = context.Background()
+				calls []string
+			)
+
+			err := Provide(c, func() (*provideTestDB, func(), error) {
+				return &provideTestDB{}, func() { calls = append(calls, "cleanup db") }, nil
+			}, tt.opts...)
+			require.NoError(t, err)
+
+			err = Setup[*provideTestRepoImpl](c,
+				Init(func(c *Container) *provideTestRepoImpl { return &provideTestRepoImpl{} }),
+				OnStop(func(ctx context.Context, r *provideTestRepoImpl) error {
+					calls = append(calls, "stop repo")
+					return nil
+				}),
+			)
+			require.NoError(t, err)
+			require.NoError(t, c.Init())
+
+			if tt.start {
+				require.NoError(t, c.Start(ctx))
+			}
+			Get[*provideTestDB](c)
+
+			require.NoError(t, c.Stop(ctx))
+			// cleanup called once
+			require.NoError(t, c.Stop(ctx))
+
+			require.Equal(t, tt.want, calls)
+		})
+	}
+}
+
+func Test_Provide_scoped_cleanup(t *testing.T) {
+	var (
+		c       = NewContainer()
+		calls   []string
+		dbs     = 0
+		ctx     = context.Background()
+		cleanup = func(dsn string) func() {
+			return func() { calls = append(calls, "cleanup "+dsn) }
+		}
+	)
+
+	err := Provide(c,
+		func() (*provideTestDB, func(), error) {
+			dbs++
+			dsn := fmt.Sprintf("tx%d", dbs)
+			return &provideTestDB{dsn: dsn}, cleanup(dsn), nil
+		},
+		Scoped(),
+		OnStop(func(ctx context.Context, db *provideTestDB) error {
+			calls = append(calls, "stop "+db.dsn)
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	s1, s2 := c.NewScope(), c.NewScope()
+	require.Equal(t, "tx1", Get[*provideTestDB](s1.Container).dsn)
+	require.Equal(t, "tx2", Get[*provideTestDB](s2.Container).dsn)
+
+	require.NoError(t, s1.Close(ctx))
+	require.Equal(t, []string{"stop tx1", "cleanup tx1"}, calls)
+
+	require.NoError(t, s2.Close(ctx))
+	require.Equal(t, []string{"stop tx1", "cleanup tx1", "stop tx2", "cleanup tx2"}, calls)
+
+	// scoped cleanup not called on Stop
+	require.NoError(t, c.Stop(ctx))
+	require.Len(t, calls, 4)
+}
+
+func Test_Provide_lifecycle(t *testing.T) {
+	var (
+		c     = NewContainer()
+		ctx   = context.Background()
+		calls []string
+	)
+
+	err := Provide(c, func() *provideTestRepoImpl { return &provideTestRepoImpl{} },
+		Stage("migrate", func(ctx context.Context, r *provideTestRepoImpl) error {
+			calls = append(calls, "migrate")
+			return nil
+		}),
+		// stage defined for type component is assignable to
+		OnStart(func(ctx context.Context, r provideTestRepo) error {
+			calls = append(calls, "start")
+			return nil
+		}),
+		OnStop(func(ctx context.Context, r *provideTestRepoImpl) error {
+			calls = append(calls, "stop")
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	require.NoError(t, c.ExecStage(ctx, "migrate"))
+	require.NoError(t, c.Start(ctx))
+	require.NoError(t, c.Stop(ctx))
+	require.Equal(t, []string{"migrate", "start", "stop"}, calls)
+}
+
+func Test_Provide_name(t *testing.T) {
+	c := NewContainer()
+
+	require.NoError(t, Value(c, &provideTestConfig{dsn: "a"}))
+	require.NoError(t, Provide(c, func(cfg *provideTestConfig) *provideTestDB {
+		return &provideTestDB{dsn: cfg.dsn}
+	}, Name("primary"), Lazy()))
+	require.NoError(t, c.Init())
+
+	require.Equal(t, "a", Get[*provideTestDB](c, Name("primary")).dsn)
+}
+
+func Test_Provide_errors(t *testing.T) {
+	errConnect := errors.New("connect")
+
+	tests := []struct {
+		name    string
+		ctor    any
+		opts    []provideOpt
+		wantErr error
+	}{
+		{name: "not function", ctor: 1, wantErr: ErrConstructor},
+		{name: "nil function", ctor: (func() *provideTestDB)(nil), wantErr: ErrConstructor},
+		{name: "no results", ctor: func() {}, wantErr: ErrConstructor},
+		{name: "second result not error", ctor: func() (*provideTestDB, int) { return nil, 0 }, wantErr: ErrConstructor},
+		{name: "variadic", ctor: func(...int) *provideTestDB { return nil }, wantErr: ErrConstructor},
+		{
+			name:    "transient with cleanup",
+			ctor:    func() (*provideTestDB, func(), error) { return nil, nil, nil },
+			opts:    []provideOpt{Transient()},
+			wantErr: ErrScopeStage,
+		},
+		{
+			name: "stage of other type",
+			ctor: func() *provideTestDB { return nil },
+			opts: []provideOpt{
+				Stage("stop", func(ctx context.Context, c *provideTestConfig) error { return nil }),
+			},
+			wantErr: ErrNotAssignable,
+		},
+		{
+			name: "stage set twice",
+			ctor: func() *provideTestDB { return nil },
+			opts: []provideOpt{
+				OnStop(func(ctx context.Context, db *provideTestDB) error { return nil }),
+				OnStop(func(ctx context.Context, db *provideTestDB) error { return nil }),
+			},
+			wantErr: ErrStageSet,
+		},
+		{
+			name: "transient with stage",
+			ctor: func() *provideTestDB { return nil },
+			opts: []provideOpt{
+				Transient(),
+				Stage("stop", func(ctx context.Context, db *provideTestDB) error { return nil }),
+			},
+			wantErr: ErrScopeStage,
+		},
+		{
+			name:    "name set twice",
+			ctor:    func() *provideTestDB { return nil },
+			opts:    []provideOpt{Name("a"), Name("b")},
+			wantErr: ErrNameSet,
+		},
+		{
+			name:    "not assignable",
+			ctor:    func() *provideTestDB { return nil },
+			opts:    []provideOpt{As[provideTestRepo]()},
+			wantErr: ErrNotAssignable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Provide(NewContainer(), tt.ctor, tt.opts...)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("constructor error", func(t *testing.T) {
+		c := NewContainer()
+		require.NoError(t, Provide(c, func() (*provideTestDB, error) { return nil, errConnect }))
+
+		err := c.Init()
+		require.ErrorIs(t, err, ErrInitComponent)
+		require.ErrorIs(t, err, errConnect)
+	})
+
+	t.Run("argument not found", func(t *testing.T) {
+		c := NewContainer()
+		require.NoError(t, Provide(c, newProvideTestRepo))
+
+		err := c.Init()
+		require.ErrorIs(t, err, ErrNotFound)
+		require.Contains(t, err.Error(), "argument 0 of")
+	})
+}
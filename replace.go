@@ -0,0 +1,102 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"slices"
+)
+
+// Replace replaces component set with di.Setup before Init. Component
+// is defined with options the same way as with di.Setup and keeps it's
+// position in initialization order. Component to replace is chosen by type
+// and di.Name option. Aliases set with di.As kept unless di.As passed.
+// Type set with di.As may be replaced too, then alias taken off component
+// and set for replacement. Useful to replace components with fakes within tests
+func Replace[T any](c *Container, opts ...setupOpt[T]) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.checkSetup(); err != nil {
+		return err
+	}
+
+	s, err := processSetupOpts(opts...)
+	if err != nil {
+		return err
+	}
+
+	var (
+		t T
+	)
+
+	coord := coordinate{
+		type_: reflect.TypeOf(&t).Elem(),
+		name:  s.name,
+	}
+
+	replaced, ok := c.components[coord]
+	if !ok {
+		return fmt.Errorf("%w: %s: %s", ErrNotFound, coord, debug.Stack())
+	}
+
+	// alias replaced with new component, aliased component kept
+	comp := replaced
+	if replaced.coord != coord {
+		comp = &component{coord: coord, module: c.module}
+	}
+
+	aliases, err := c.aliases(coord, s.as, comp)
+	if err != nil {
+		return err
+	}
+
+	// stage definitions collected again as replaced component
+	// stages may be the only ones defining stage
+	defs := make(map[string]stageDef, len(c.stages))
+	for _, other := range c.initOrder {
+		if other == coord {
+			continue
+		}
+		for name, st := range c.components[other].stages {
+			// definitions of other components merged on setup
+			defs[name], _ = mergeStageDef(defs, name, st.def)
+		}
+	}
+
+	for name, st := range s.stages {
+		def, err := mergeStageDef(defs, name, st.def)
+		if err != nil {
+			return fmt.Errorf("%w: for %s: %s", err, coord, debug.Stack())
+		}
+		defs[name] = def
+	}
+
+	switch {
+	case comp != replaced:
+		// replacement initialized before component alias taken off
+		c.initOrder = slices.Insert(c.initOrder, slices.Index(c.initOrder, replaced.coord), coord)
+		c.components[coord] = comp
+	case len(s.as) > 0:
+		for alias, other := range c.components {
+			if other == comp && alias != coord {
+				delete(c.components, alias)
+			}
+		}
+	}
+	for _, alias := range aliases {
+		c.components[alias] = comp
+	}
+
+	comp.initFn = s.initFn
+	comp.stages = s.stages
+	comp.onStart = s.onStart
+	comp.onStop = s.onStop
+	comp.lazy = s.lazy
+	comp.private = s.private
+	comp.scope = s.scope
+
+	c.stages = defs
+
+	return nil
+}
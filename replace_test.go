@@ -0,0 +1,174 @@
+package di
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type replaceTestDB interface{ Query() string }
+type replaceTestRealDB struct{}
+type replaceTestRepo struct{ db replaceTestDB }
+
+func (replaceTestRealDB) Query() string { return "real" }
+
+type replaceTestFakeDB struct{}
+
+func (replaceTestFakeDB) Query() string { return "fake" }
+
+func Test_Replace(t *testing.T) {
+	var (
+		c     = NewContainer()
+		order []string
+	)
+
+	err := Setup[replaceTestDB](c,
+		Init(func(c *Container) replaceTestDB {
+			order = append(order, "real db")
+			return replaceTestRealDB{}
+		}),
+		Stage("stop", func(ctx context.Context, db replaceTestDB) error { return nil }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*replaceTestRepo](c,
+		Init(func(c *Container) *replaceTestRepo {
+			order = append(order, "repo")
+			return &replaceTestRepo{db: Get[replaceTestDB](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Replace[replaceTestDB](c,
+		Init(func(c *Container) replaceTestDB {
+			order = append(order, "fake db")
+			return replaceTestFakeDB{}
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	require.Equal(t, []string{"fake db", "repo"}, order)
+	require.Equal(t, "fake", Get[*replaceTestRepo](c).db.Query())
+
+	// replaced component stages removed
+	require.Empty(t, c.Graph().Nodes[0].Stages)
+}
+
+func Test_Replace_errors(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[*replaceTestRealDB](c,
+		Init(func(c *Container) *replaceTestRealDB { return &replaceTestRealDB{} }),
+		As[replaceTestDB](),
+	)
+	require.NoError(t, err)
+
+	err = Replace[*replaceTestRealDB](c,
+		Name("other"),
+		Init(func(c *Container) *replaceTestRealDB { return &replaceTestRealDB{} }),
+	)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	err = Replace[*replaceTestRealDB](c,
+		Init(func(c *Container) *replaceTestRealDB { return &replaceTestRealDB{} }),
+		As[replaceTestFakeDB](),
+	)
+	require.ErrorIs(t, err, ErrNotAssignable)
+
+	err = Replace[*replaceTestRealDB](c)
+	require.ErrorIs(t, err, ErrInitNotSet)
+
+	require.NoError(t, c.Init())
+
+	err = Replace[*replaceTestRealDB](c,
+		Init(func(c *Container) *replaceTestRealDB { return &replaceTestRealDB{} }),
+	)
+	require.ErrorIs(t, err, ErrInitialized)
+}
+
+func Test_Replace_aliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func() (*Container, error)
+		wantDB  string
+		wantAll int
+	}{
+		{
+			name: "aliases kept",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*replaceTestRealDB](c,
+					Init(func(c *Container) *replaceTestRealDB { return &replaceTestRealDB{} }),
+					As[replaceTestDB](),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Replace[*replaceTestRealDB](c,
+					Init(func(c *Container) *replaceTestRealDB { return &replaceTestRealDB{} }),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			wantDB:  "real",
+			wantAll: 1,
+		},
+		{
+			name: "alias replaced",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*replaceTestRealDB](c,
+					Init(func(c *Container) *replaceTestRealDB { return &replaceTestRealDB{} }),
+					As[replaceTestDB](),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*replaceTestRepo](c,
+					Init(func(c *Container) *replaceTestRepo {
+						return &replaceTestRepo{db: Get[replaceTestDB](c)}
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Replace[replaceTestDB](c,
+					Init(func(c *Container) replaceTestDB { return replaceTestFakeDB{} }),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			wantDB: "fake",
+			// aliased component kept
+			wantAll: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.setup()
+			require.NoError(t, err)
+			require.NoError(t, c.Init())
+
+			db, err := GetE[replaceTestDB](c)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantDB, db.Query())
+
+			_, err = GetE[*replaceTestRealDB](c)
+			require.NoError(t, err)
+
+			require.Len(t, All[replaceTestDB](c), tt.wantAll)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+type runOpt interface {
+	runOpt()
+}
+
+type withStartStages []string
+type withStopStages []string
+type withShutdownTimeout time.Duration
+type withSignals []os.Signal
+
+func (withStartStages) runOpt()     {}
+func (withStopStages) runOpt()      {}
+func (withShutdownTimeout) runOpt() {}
+func (withSignals) runOpt()         {}
+
+// StartStages defines stages executed on Run after Start. Default is "start"
+func StartStages(names ...string) withStartStages { return names }
+
+// StopStages defines stages executed on shutdown before Stop. Default is "stop"
+func StopStages(names ...string) withStopStages { return names }
+
+// ShutdownTimeout defines time given to shutdown. Default is 30 seconds
+func ShutdownTimeout(d time.Duration) withShutdownTimeout { return withShutdownTimeout(d) }
+
+// Signals defines signals to shutdown on. Default are SIGINT and SIGTERM
+func Signals(sigs ...os.Signal) withSignals { return sigs }
+
+type runConfig struct {
+	startStages []string
+	stopStages  []string
+	timeout     time.Duration
+	signals     []os.Signal
+}
+
+func processRunOpts(opts ...runOpt) runConfig {
+	cfg := runConfig{
+		startStages: []string{"start"},
+		stopStages:  []string{"stop"},
+		timeout:     30 * time.Second,
+		signals:     []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+
+	for _, o := range opts {
+		switch o := o.(type) {
+		case withStartStages:
+			cfg.startStages = o
+		case withStopStages:
+			cfg.stopStages = o
+		case withShutdownTimeout:
+			cfg.timeout = time.Duration(o)
+		case withSignals:
+			cfg.signals = o
+		}
+	}
+
+	return cfg
+}
+
+func (c *Container) isInitialized() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.initialized
+}
+
+// Run inits container if not initialized, calls Start and executes start stages.
+// If Init fails Stop called to clean up components built.
+// Then waits for ctx done or signal received and shuts down with executing stop stages
+// and calling Stop. Stop stages executed only if all the start stages succeeded.
+// Signal received while starting cancels context passed into start stages and
+// shuts down after start. Second signal received while starting or shutting down
+// makes Run return immediately with ErrShutdownForced
+func (c *Container) Run(ctx context.Context, opts ...runOpt) error {
+	if err := c.checkNotScope(); err != nil {
+		return err
+	}
+
+	cfg := processRunOpts(opts...)
+
+	if !c.isInitialized() {
+		if err := c.Init(); err != nil {
+			// cleanups of components built before error called
+			return errors.Join(err, c.Stop(context.WithoutCancel(ctx)))
+		}
+	}
+
+	// second signal not dropped while first one handled
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, cfg.signals...)
+	defer signal.Stop(sigs)
+
+	interrupted, err := c.start(ctx, cfg, sigs)
+	if errors.Is(err, ErrShutdownForced) {
+		return err
+	}
+	if err != nil {
+		// components started before error stopped
+		return errors.Join(err, c.shutdown(ctx, cfg, false, sigs))
+	}
+
+	if !interrupted {
+		select {
+		case <-ctx.Done():
+		case <-sigs:
+		}
+	}
+
+	return c.shutdown(ctx, cfg, true, sigs)
+}
+
+// start calls Start and executes start stages. Returns true if signal received while starting
+func (c *Container) start(ctx context.Context, cfg runConfig, sigs <-chan os.Signal) (bool, error) {
+	ctx, cnl := context.WithCancelCause(ctx)
+	defer cnl(nil)
+
+	done := make(chan error, 1)
+	go func() {
+		if err := c.Start(ctx); err != nil {
+			done <- err
+			return
+		}
+
+		for _, name := range cfg.startStages {
+			if err := c.ExecStage(ctx, name); err != nil {
+				done <- err
+				return
+			}
+		}
+
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		return false, err
+	case sig := <-sigs:
+		cnl(fmt.Errorf("%s received", sig))
+	}
+
+	select {
+	case err := <-done:
+		return true, err
+	case sig := <-sigs:
+		return true, fmt.Errorf("%w: %s received", ErrShutdownForced, sig)
+	}
+}
+
+// shutdown executes stop stages if stages is true and calls Stop
+func (c *Container) shutdown(ctx context.Context, cfg runConfig, stages bool, sigs <-chan os.Signal) error {
+	// run context may be already cancelled
+	ctx, cnl := context.WithTimeoutCause(
+		context.WithoutCancel(ctx),
+		cfg.timeout,
+		fmt.Errorf("%w: %s", ErrShutdownTimeout, cfg.timeout),
+	)
+	defer cnl()
+
+	done := make(chan error, 1)
+	go func() {
+		var errs []error
+		// stop stages of components not started are not executed
+		if stages {
+			for _, name := range cfg.stopStages {
+				errs = append(errs, c.ExecStage(ctx, name))
+			}
+		}
+		errs = append(errs, c.Stop(ctx))
+
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case sig := <-sigs:
+		return fmt.Errorf("%w: %s received", ErrShutdownForced, sig)
+	}
+}
@@ -0,0 +1,276 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type runTestType struct{}
+
+func Test_Run_shutdown_on_context_done(t *testing.T) {
+	var (
+		c           = NewContainer()
+		calls       = make(chan string, 4)
+		ctx, cancel = context.WithCancel(context.Background())
+		errs        = make(chan error)
+	)
+
+	err := Setup[*runTestType](c,
+		Init(func(c *Container) *runTestType { return &runTestType{} }),
+		OnStart(func(ctx context.Context, r *runTestType) error {
+			calls <- "on start"
+			return nil
+		}),
+		OnStop(func(ctx context.Context, r *runTestType) error {
+			calls <- "on stop"
+			return nil
+		}),
+		Stage("start", func(ctx context.Context, r *runTestType) error {
+			calls <- "start"
+			return nil
+		}),
+		Stage("stop", func(ctx context.Context, r *runTestType) error {
+			calls <- "stop"
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	go func() { errs <- c.Run(ctx) }()
+
+	require.Equal(t, "on start", <-calls)
+	require.Equal(t, "start", <-calls)
+
+	cancel()
+	require.NoError(t, <-errs)
+
+	require.Equal(t, "stop", <-calls)
+	require.Equal(t, "on stop", <-calls)
+}
+
+func Test_Run_shutdown_on_signal(t *testing.T) {
+	var (
+		c     = NewContainer()
+		calls = make(chan string, 4)
+		errs  = make(chan error)
+	)
+
+	err := Setup[*runTestType](c,
+		Init(func(c *Container) *runTestType { return &runTestType{} }),
+		OnStop(func(ctx context.Context, r *runTestType) error {
+			calls <- "on stop"
+			return nil
+		}),
+		Stage("start", func(ctx context.Context, r *runTestType) error {
+			calls <- "start"
+			return nil
+		}),
+		Stage("stop", func(ctx context.Context, r *runTestType) error {
+			calls <- "stop"
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	go func() { errs <- c.Run(context.Background(), Signals(os.Interrupt), StopStages()) }()
+
+	require.Equal(t, "start", <-calls)
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(os.Interrupt))
+
+	require.NoError(t, <-errs)
+	require.Equal(t, "on stop", <-calls)
+	require.Empty(t, calls)
+}
+
+func Test_Run_shutdown_forced_on_second_signal(t *testing.T) {
+	var (
+		c       = NewContainer()
+		calls   = make(chan string, 4)
+		unblock = make(chan struct{})
+		errs    = make(chan error)
+	)
+	defer close(unblock)
+
+	err := Setup[*runTestType](c,
+		Init(func(c *Container) *runTestType { return &runTestType{} }),
+		// stop hook not returning until test finished
+		OnStop(func(ctx context.Context, r *runTestType) error {
+			calls <- "on stop"
+			<-unblock
+			return nil
+		}),
+		Stage("start", func(ctx context.Context, r *runTestType) error {
+			calls <- "start"
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	go func() { errs <- c.Run(context.Background(), Signals(os.Interrupt)) }()
+
+	require.Equal(t, "start", <-calls)
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(os.Interrupt))
+
+	require.Equal(t, "on stop", <-calls)
+
+	require.NoError(t, p.Signal(os.Interrupt))
+	require.ErrorIs(t, <-errs, ErrShutdownForced)
+}
+
+func Test_Run_shutdown_timeout(t *testing.T) {
+	var (
+		c           = NewContainer()
+		unblock     = make(chan struct{})
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+	defer close(unblock)
+
+	err := Setup[*runTestType](c,
+		Init(func(c *Container) *runTestType { return &runTestType{} }),
+		OnStop(func(ctx context.Context, r *runTestType) error {
+			<-unblock
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	cancel()
+	err = c.Run(ctx, ShutdownTimeout(10*time.Millisecond))
+	require.ErrorIs(t, err, ErrShutdownTimeout)
+}
+
+func Test_Run_stop_stages_not_executed_on_start_error(t *testing.T) {
+	var (
+		c        = NewContainer()
+		calls    []string
+		startErr = errors.New("listen")
+	)
+
+	err := Setup[*runTestType](c,
+		Init(func(c *Container) *runTestType { return &runTestType{} }),
+		Stage("start", func(ctx context.Context, r *runTestType) error { return startErr }),
+		Stage("stop", func(ctx context.Context, r *runTestType) error {
+			calls = append(calls, "stop")
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Run(context.Background())
+	require.ErrorIs(t, err, startErr)
+	require.Empty(t, calls)
+}
+
+func Test_Run_signal_while_starting(t *testing.T) {
+	var (
+		c     = NewContainer()
+		calls = make(chan string, 4)
+		errs  = make(chan error)
+	)
+
+	err := Setup[*runTestType](c,
+		Init(func(c *Container) *runTestType { return &runTestType{} }),
+		OnStop(func(ctx context.Context, r *runTestType) error {
+			calls <- "on stop"
+			return nil
+		}),
+		// slow start stage cancelled with signal
+		Stage("start", func(ctx context.Context, r *runTestType) error {
+			calls <- "start"
+			<-ctx.Done()
+			return context.Cause(ctx)
+		}),
+		Stage("stop", func(ctx context.Context, r *runTestType) error {
+			calls <- "stop"
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	go func() { errs <- c.Run(context.Background(), Signals(os.Interrupt)) }()
+
+	require.Equal(t, "start", <-calls)
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(os.Interrupt))
+
+	err = <-errs
+	require.ErrorIs(t, err, ErrExecuteStage)
+	require.Contains(t, err.Error(), "interrupt received")
+	require.Equal(t, "on stop", <-calls)
+	require.Empty(t, calls)
+}
+
+func Test_Run_shutdown_forced_while_starting(t *testing.T) {
+	var (
+		c         = NewContainer()
+		started   = make(chan struct{})
+		cancelled = make(chan struct{})
+		unblock   = make(chan struct{})
+		errs      = make(chan error)
+	)
+	defer close(unblock)
+
+	err := Setup[*runTestType](c,
+		Init(func(c *Container) *runTestType { return &runTestType{} }),
+		// start stage not returning when context cancelled
+		Stage("start", func(ctx context.Context, r *runTestType) error {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			<-unblock
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	go func() { errs <- c.Run(context.Background(), Signals(os.Interrupt)) }()
+
+	<-started
+
+	p, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.NoError(t, p.Signal(os.Interrupt))
+
+	<-cancelled
+	require.NoError(t, p.Signal(os.Interrupt))
+
+	require.ErrorIs(t, <-errs, ErrShutdownForced)
+}
+
+func Test_Run_stop_called_on_init_error(t *testing.T) {
+	var (
+		c       = NewContainer()
+		cleaned = false
+		initErr = errors.New("connect")
+	)
+
+	err := Provide(c, func() (*runTestType, func(), error) {
+		return &runTestType{}, func() { cleaned = true }, nil
+	})
+	require.NoError(t, err)
+
+	err = Setup[int](c,
+		InitE(func(c *Container) (int, error) {
+			Get[*runTestType](c)
+			return 0, initErr
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Run(context.Background())
+	require.ErrorIs(t, err, initErr)
+	require.True(t, cleaned)
+}
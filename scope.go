@@ -0,0 +1,138 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Scope is child container holding own instances of scoped components.
+// Singleton components resolved from parent container. Setup, Init, Start,
+// Stop, ExecStage and Run return ErrWithinScope when called on scope
+type Scope struct {
+	*Container
+
+	mu        sync.Mutex
+	closed    bool
+	instances map[coordinate]*scopedInstance
+	// initialized are instances in order they were initialized
+	initialized []*scopedInstance
+}
+
+type scopedInstance struct {
+	mu      sync.Mutex
+	done    bool
+	comp    *component
+	val     any
+	err     error
+	cleanup func()
+}
+
+// NewScope creates scope to resolve components set with di.Scoped.
+// Scope should be closed with Close when not needed anymore
+func (c *Container) NewScope() *Scope {
+	s := &Scope{instances: make(map[coordinate]*scopedInstance)}
+	s.Container = &Container{state: c.state, scope: s}
+
+	return s
+}
+
+// checkNotScope returns error if container is scope. Setup and lifecycle
+// of application managed with root container, scope only closed with Close
+func (c *Container) checkNotScope() error {
+	if c.scope != nil {
+		return ErrWithinScope
+	}
+
+	return nil
+}
+
+// inScope checks if component can be resolved with container
+func (c *Container) inScope(comp *component) bool {
+	return comp.scope != scopeScoped || c.scope != nil
+}
+
+func (s *Scope) instance(comp *component) (*scopedInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("%w: %s", ErrScopeClosed, comp.coord)
+	}
+
+	inst, ok := s.instances[comp.coord]
+	if !ok {
+		inst = &scopedInstance{comp: comp}
+		s.instances[comp.coord] = inst
+	}
+
+	return inst, nil
+}
+
+func (s *Scope) setCleanup(comp *component, cleanup func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.instances[comp.coord].cleanup = cleanup
+}
+
+// resolve returns scoped component instance initializing it once within scope
+func (s *Scope) resolve(c *Container, comp *component) (any, error) {
+	inst, err := s.instance(comp)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := c.lock(inst, &inst.mu, comp.coord)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if inst.done {
+		return inst.val, inst.err
+	}
+
+	inst.val, inst.err = c.initComponent(comp)
+	inst.done = true
+	if inst.err != nil {
+		return nil, inst.err
+	}
+
+	s.mu.Lock()
+	s.initialized = append(s.initialized, inst)
+	s.mu.Unlock()
+
+	return inst.val, nil
+}
+
+// Close calls functions defined with OnStop and cleanup functions returned
+// from di.Provide constructors for scoped components initialized within scope
+// in reverse order. Close continues on error and returns all the errors occurred
+func (s *Scope) Close(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	initialized := s.initialized
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(initialized) - 1; i >= 0; i-- {
+		inst := initialized[i]
+		if inst.comp.onStop != nil {
+			if err := inst.comp.onStop(ctx, inst.val); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %s: %w", ErrStop, inst.comp, err))
+			}
+		}
+
+		if inst.cleanup != nil {
+			inst.cleanup()
+		}
+	}
+
+	return errors.Join(errs...)
+}
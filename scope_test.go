@@ -0,0 +1,199 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type scopeTestDB struct{}
+type scopeTestTx struct {
+	db *scopeTestDB
+	id int
+}
+type scopeTestRepo struct{ tx *scopeTestTx }
+type scopeTestService struct{ tx *scopeTestTx }
+
+func Test_Scope(t *testing.T) {
+	var (
+		c      = NewContainer()
+		closed []string
+		txs    = 0
+	)
+
+	err := Setup[*scopeTestDB](c,
+		Init(func(c *Container) *scopeTestDB { return &scopeTestDB{} }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*scopeTestTx](c,
+		Scoped(),
+		Init(func(c *Container) *scopeTestTx {
+			txs++
+			return &scopeTestTx{db: Get[*scopeTestDB](c), id: txs}
+		}),
+		OnStop(func(ctx context.Context, tx *scopeTestTx) error {
+			closed = append(closed, "tx")
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*scopeTestRepo](c,
+		Scoped(),
+		Init(func(c *Container) *scopeTestRepo {
+			return &scopeTestRepo{tx: Get[*scopeTestTx](c)}
+		}),
+		OnStop(func(ctx context.Context, r *scopeTestRepo) error {
+			closed = append(closed, "repo")
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	var (
+		s1 = c.NewScope()
+		s2 = c.NewScope()
+	)
+
+	repo1, err := GetE[*scopeTestRepo](s1.Container)
+	require.NoError(t, err)
+
+	tx1, err := GetE[*scopeTestTx](s1.Container)
+	require.NoError(t, err)
+	require.Same(t, tx1, repo1.tx)
+
+	repo2, err := GetE[*scopeTestRepo](s2.Container)
+	require.NoError(t, err)
+	require.NotSame(t, repo1.tx, repo2.tx)
+
+	// singleton resolved from parent
+	db, err := GetE[*scopeTestDB](c)
+	require.NoError(t, err)
+	require.Same(t, db, repo1.tx.db)
+	require.Same(t, db, repo2.tx.db)
+
+	err = s1.Close(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"repo", "tx"}, closed)
+
+	_, err = GetE[*scopeTestRepo](s1.Container)
+	require.ErrorIs(t, err, ErrScopeClosed)
+
+	// closing twice does nothing
+	err = s1.Close(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"repo", "tx"}, closed)
+}
+
+func Test_Scope_close_continues_on_error(t *testing.T) {
+	var (
+		c        = NewContainer()
+		closed   []string
+		closeErr = errors.New("close error")
+	)
+
+	err := Setup[*scopeTestTx](c,
+		Scoped(),
+		Init(func(c *Container) *scopeTestTx { return &scopeTestTx{} }),
+		OnStop(func(ctx context.Context, tx *scopeTestTx) error {
+			closed = append(closed, "tx")
+			return closeErr
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*scopeTestRepo](c,
+		Scoped(),
+		Init(func(c *Container) *scopeTestRepo {
+			return &scopeTestRepo{tx: Get[*scopeTestTx](c)}
+		}),
+		OnStop(func(ctx context.Context, r *scopeTestRepo) error {
+			closed = append(closed, "repo")
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	s := c.NewScope()
+
+	_, err = GetE[*scopeTestRepo](s.Container)
+	require.NoError(t, err)
+
+	err = s.Close(context.Background())
+	require.ErrorIs(t, err, closeErr)
+	require.ErrorIs(t, err, ErrStop)
+	require.Equal(t, []string{"repo", "tx"}, closed)
+}
+
+func Test_error_scoped_out_of_scope(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[*scopeTestTx](c,
+		Scoped(),
+		Init(func(c *Container) *scopeTestTx { return &scopeTestTx{} }),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	_, err = GetE[*scopeTestTx](c)
+	require.ErrorIs(t, err, ErrOutOfScope)
+}
+
+func Test_error_singleton_depends_on_scoped(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[*scopeTestTx](c,
+		Scoped(),
+		Init(func(c *Container) *scopeTestTx { return &scopeTestTx{} }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*scopeTestService](c,
+		Lazy(),
+		Init(func(c *Container) *scopeTestService {
+			return &scopeTestService{tx: Get[*scopeTestTx](c)}
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	// singleton initialized within scope can not capture scoped component
+	_, err = GetE[*scopeTestService](c.NewScope().Container)
+	require.ErrorIs(t, err, ErrOutOfScope)
+}
+
+func Test_error_lifecycle_within_scope(t *testing.T) {
+	c := NewContainer()
+	require.NoError(t, Value(c, &scopeTestDB{}))
+	require.NoError(t, c.Init())
+	require.NoError(t, c.Start(context.Background()))
+
+	s := c.NewScope()
+	ctx := context.Background()
+
+	require.ErrorIs(t, s.Init(), ErrWithinScope)
+	require.ErrorIs(t, s.Start(ctx), ErrWithinScope)
+	require.ErrorIs(t, s.Stop(ctx), ErrWithinScope)
+	require.ErrorIs(t, s.ExecStage(ctx, "stop"), ErrWithinScope)
+	require.ErrorIs(t, s.Run(ctx), ErrWithinScope)
+	require.ErrorIs(t, s.Install(Module{Name: "scope"}), ErrWithinScope)
+	require.ErrorIs(t, Value(s.Container, &scopeTestService{}), ErrWithinScope)
+
+	// root container not stopped by scope
+	require.ErrorIs(t, c.Start(ctx), ErrStarted)
+	require.NoError(t, s.Close(ctx))
+	require.NoError(t, c.Stop(ctx))
+}
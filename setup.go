@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"runtime/debug"
+	"slices"
+	"time"
 )
 
 type setupOpt[T any] interface {
@@ -14,16 +16,136 @@ type setupOpt[T any] interface {
 type withInitE[T any] func(*Container) (T, error)
 type withInit[T any] func(*Container) T
 type withStage[T any] struct {
-	name string
-	fn   func(context.Context, T) error
+	name    string
+	fn      func(context.Context, T) error
+	def     stageDef
+	timeout time.Duration
+}
+type withOnStart[T any] func(context.Context, T) error
+type withOnStop[T any] func(context.Context, T) error
+type withAs struct {
+	type_ reflect.Type
+}
+type withLazy struct{}
+type withPrivate struct{}
+type withScope scope
+
+func (o withName) setupOpt()     {}
+func (withInitE[T]) setupOpt()   {}
+func (withInit[T]) setupOpt()    {}
+func (withStage[T]) setupOpt()   {}
+func (withOnStart[T]) setupOpt() {}
+func (withOnStop[T]) setupOpt()  {}
+func (withAs) setupOpt()         {}
+func (withLazy) setupOpt()       {}
+func (withPrivate) setupOpt()    {}
+func (withScope) setupOpt()      {}
+
+// Lazy makes component initialized on first request with Get/GetE
+// instead of Init. Component requested within init function of not lazy
+// component is initialized on Init
+func Lazy() withLazy { return withLazy{} }
+
+// Private makes component visible within module it is set within only.
+// Requesting component outside of module returns ErrNotVisible
+func Private() withPrivate { return withPrivate{} }
+
+// Transient makes new component instance initialized on every request
+// with Get/GetE. Transient component can not have stages
+func Transient() withScope { return withScope(scopeTransient) }
+
+// Scoped makes component instance initialized once within every scope
+// created with NewScope. Scoped component can not have stages except
+// OnStop which is called on scope close
+func Scoped() withScope { return withScope(scopeScoped) }
+
+// As makes component retrievable as type I in addition to it's own type.
+// Component type should be assignable to I
+func As[I any]() withAs {
+	var i I
+	return withAs{type_: reflect.TypeOf(&i).Elem()}
+}
+
+// setup is component definition collected from setup options
+type setup struct {
+	name    string
+	initFn  func(*Container) (any, error)
+	stages  map[string]stage
+	onStart func(context.Context, any) error
+	onStop  func(context.Context, any) error
+	// as are additional types component retrievable as
+	as      []reflect.Type
+	lazy    bool
+	private bool
+	scope   scope
+	// nameSet and scopeSet detect option passed twice
+	nameSet  bool
+	scopeSet bool
+}
+
+type stage struct {
+	fn      func(context.Context, any) error
+	def     stageDef
+	timeout time.Duration
+}
+
+// lifecycleOpt is stage or lifecycle hook option of component of any type
+type lifecycleOpt interface {
+	// componentType returns type stage function accepts
+	componentType() reflect.Type
+	apply(s *setup) error
 }
 
-func (o withName) setupOpt()   {}
-func (withInitE[T]) setupOpt() {}
-func (withInit[T]) setupOpt()  {}
-func (withStage[T]) setupOpt() {}
+func (o withStage[T]) componentType() reflect.Type   { return typeOf[T]() }
+func (o withOnStart[T]) componentType() reflect.Type { return typeOf[T]() }
+func (o withOnStop[T]) componentType() reflect.Type  { return typeOf[T]() }
+
+func (o withStage[T]) apply(s *setup) error {
+	if _, ok := s.stages[o.name]; ok {
+		return fmt.Errorf("%w: %s", ErrStageSet, debug.Stack())
+	}
+	if o.fn == nil {
+		return fmt.Errorf("%w: %s", ErrStageNotSet, debug.Stack())
+	}
+
+	s.stages[o.name] = stage{fn: stageFn(o.fn), def: o.def, timeout: o.timeout}
+	return nil
+}
+
+func (o withOnStart[T]) apply(s *setup) error {
+	if s.onStart != nil {
+		return fmt.Errorf("%w: on start: %s", ErrStageSet, debug.Stack())
+	}
+	if o == nil {
+		return fmt.Errorf("%w: on start: %s", ErrStageNotSet, debug.Stack())
+	}
+
+	s.onStart = stageFn(o)
+	return nil
+}
+
+func (o withOnStop[T]) apply(s *setup) error {
+	if s.onStop != nil {
+		return fmt.Errorf("%w: on stop: %s", ErrStageSet, debug.Stack())
+	}
+	if o == nil {
+		return fmt.Errorf("%w: on stop: %s", ErrStageNotSet, debug.Stack())
+	}
+
+	s.onStop = stageFn(o)
+	return nil
+}
+
+func typeOf[T any]() reflect.Type {
+	var t T
+	return reflect.TypeOf(&t).Elem()
+}
 
 func (c *Container) checkSetup() error {
+	if err := c.checkNotScope(); err != nil {
+		return fmt.Errorf("%w: %s", err, debug.Stack())
+	}
+
 	if c.initialized {
 		return fmt.Errorf("%w: %s", ErrInitialized, debug.Stack())
 	}
@@ -35,67 +157,91 @@ func (c *Container) checkSetup() error {
 	return nil
 }
 
-func processSetupOpts[T any](opts ...setupOpt[T]) (
-	// name
-	string,
-	// init function
-	func(*Container) (any, error),
-	// stages functions
-	map[string]func(context.Context, T) error,
-	error,
-) {
+// applyComponentOpt applies option common for di.Setup and di.Provide.
+// Other options ignored
+func (s *setup) applyComponentOpt(o any) error {
+	switch o := o.(type) {
+	case withName:
+		if s.nameSet {
+			return fmt.Errorf("%w: %s", ErrNameSet, debug.Stack())
+		}
+
+		s.name = string(o)
+		s.nameSet = true
+	case withAs:
+		s.as = append(s.as, o.type_)
+	case withLazy:
+		s.lazy = true
+	case withPrivate:
+		s.private = true
+	case withScope:
+		if s.scopeSet {
+			return fmt.Errorf("%w: %s", ErrScopeSet, debug.Stack())
+		}
+
+		s.scope = scope(o)
+		s.scopeSet = true
+	}
+
+	return nil
+}
+
+func processSetupOpts[T any](opts ...setupOpt[T]) (setup, error) {
 	var (
-		t        T
-		name     = ""
-		nameSet  = false
-		initFn   func(*Container) (any, error)
-		stageFns = make(map[string]func(context.Context, T) error)
+		t T
+		s = setup{stages: make(map[string]stage)}
 	)
 
 	for _, o := range opts {
 		switch o := o.(type) {
-		case withName:
-			if nameSet {
-				return "", nil, nil, fmt.Errorf("%w: %s", ErrNameSet, debug.Stack())
-			}
-
-			name = string(o)
-			nameSet = true
 		case withInitE[T]:
 			if o == nil {
-				return "", nil, nil, fmt.Errorf("%w: for type (%s): %s", ErrInitNotSet, reflect.TypeOf(&t).Elem(), debug.Stack())
+				return setup{}, fmt.Errorf("%w: for type (%s): %s", ErrInitNotSet, reflect.TypeOf(&t).Elem(), debug.Stack())
 			}
-			if initFn != nil {
-				return "", nil, nil, fmt.Errorf("%w: for type (%s): %s", ErrInitSet, reflect.TypeOf(&t).Elem(), debug.Stack())
+			if s.initFn != nil {
+				return setup{}, fmt.Errorf("%w: for type (%s): %s", ErrInitSet, reflect.TypeOf(&t).Elem(), debug.Stack())
 			}
 
-			initFn = func(c *Container) (any, error) { return o(c) }
+			s.initFn = func(c *Container) (any, error) { return o(c) }
 		case withInit[T]:
 			if o == nil {
-				return "", nil, nil, fmt.Errorf("%w: for type (%s): %s", ErrInitNotSet, reflect.TypeOf(&t).Elem(), debug.Stack())
+				return setup{}, fmt.Errorf("%w: for type (%s): %s", ErrInitNotSet, reflect.TypeOf(&t).Elem(), debug.Stack())
 			}
-			if initFn != nil {
-				return "", nil, nil, fmt.Errorf("%w: for type (%s): %s", ErrInitSet, reflect.TypeOf(&t).Elem(), debug.Stack())
+			if s.initFn != nil {
+				return setup{}, fmt.Errorf("%w: for type (%s): %s", ErrInitSet, reflect.TypeOf(&t).Elem(), debug.Stack())
 			}
 
-			initFn = func(c *Container) (any, error) { return o(c), nil }
-		case withStage[T]:
-			if _, ok := stageFns[o.name]; ok {
-				return "", nil, nil, fmt.Errorf("%w: %s", ErrStageSet, debug.Stack())
+			s.initFn = func(c *Container) (any, error) { return o(c), nil }
+		case withStage[T], withOnStart[T], withOnStop[T]:
+			if err := o.(lifecycleOpt).apply(&s); err != nil {
+				return setup{}, err
 			}
-			if o.fn == nil {
-				return "", nil, nil, fmt.Errorf("%w: %s", ErrStageNotSet, debug.Stack())
+		default:
+			if err := s.applyComponentOpt(o); err != nil {
+				return setup{}, err
 			}
-
-			stageFns[o.name] = o.fn
 		}
 	}
 
-	if initFn == nil {
-		return "", nil, nil, fmt.Errorf("%w: for type (%s): %s", ErrInitNotSet, reflect.TypeOf(&t).Elem(), debug.Stack())
+	if err := s.check(reflect.TypeOf(&t).Elem()); err != nil {
+		return setup{}, err
+	}
+
+	return s, nil
+}
+
+// check checks component definition is complete and consistent
+func (s setup) check(type_ reflect.Type) error {
+	if s.initFn == nil {
+		return fmt.Errorf("%w: for type (%s): %s", ErrInitNotSet, type_, debug.Stack())
+	}
+
+	// scoped component stopped on scope close
+	if s.scope != scopeSingleton && (len(s.stages) > 0 || s.onStart != nil || (s.onStop != nil && s.scope != scopeScoped)) {
+		return fmt.Errorf("%w: for %s type (%s): %s", ErrScopeStage, s.scope, type_, debug.Stack())
 	}
 
-	return name, initFn, stageFns, nil
+	return nil
 }
 
 func Setup[T any](c *Container, opts ...setupOpt[T]) error {
@@ -106,7 +252,7 @@ func Setup[T any](c *Container, opts ...setupOpt[T]) error {
 		return err
 	}
 
-	name, initFn, stageFns, err := processSetupOpts(opts...)
+	s, err := processSetupOpts(opts...)
 	if err != nil {
 		return err
 	}
@@ -115,24 +261,106 @@ func Setup[T any](c *Container, opts ...setupOpt[T]) error {
 		t T
 	)
 
+	_, err = c.set(reflect.TypeOf(&t).Elem(), s)
+	return err
+}
+
+// set sets component of type defined with setup. Container should be locked
+func (c *Container) set(type_ reflect.Type, s setup) (*component, error) {
 	coord := coordinate{
-		type_: reflect.TypeOf(&t).Elem(),
-		name:  name,
+		type_: type_,
+		name:  s.name,
 	}
 
-	if _, ok := c.components[coord]; ok {
-		return fmt.Errorf("%w: %s", ErrComponentSet, debug.Stack())
+	if set, ok := c.components[coord]; ok {
+		return nil, fmt.Errorf("%w: %s: %s", ErrComponentSet, set, debug.Stack())
 	}
 
-	c.components[coord] = component{
-		initFn: initFn, // set to nil after initialization
+	aliases, err := c.aliases(coord, s.as, nil)
+	if err != nil {
+		return nil, err
 	}
 
+	defs := make(map[string]stageDef, len(s.stages))
+	for name, st := range s.stages {
+		def, err := mergeStageDef(c.stages, name, st.def)
+		if err != nil {
+			return nil, fmt.Errorf("%w: for %s: %s", err, coord, debug.Stack())
+		}
+		defs[name] = def
+	}
+
+	comp := &component{
+		initFn:  s.initFn, // set to nil after initialization
+		coord:   coord,
+		module:  c.module,
+		stages:  s.stages,
+		onStart: s.onStart,
+		onStop:  s.onStop,
+		lazy:    s.lazy,
+		private: s.private,
+		scope:   s.scope,
+	}
+
+	for name := range s.stages {
+		c.stages[name] = defs[name]
+	}
+
+	c.components[coord] = comp
+	for _, alias := range aliases {
+		c.components[alias] = comp
+	}
 	c.initOrder = append(c.initOrder, coord)
 
-	for name, fn := range stageFns {
-		c.stages[name] = append(c.stages[name], stageFn(c, coord, fn))
+	return comp, nil
+}
+
+// Value sets already constructed component. Options are the same as
+// for di.Setup except init function which should not be set
+func Value[T any](c *Container, v T, opts ...setupOpt[T]) error {
+	// full slice expression makes append not to modify opts
+	opts = append(opts[:len(opts):len(opts)], withInit[T](func(*Container) T { return v }))
+	return Setup(c, opts...)
+}
+
+// aliases returns coordinates component retrievable with in addition to it's own.
+// Coordinates taken by self are not considered as set
+func (c *Container) aliases(coord coordinate, as []reflect.Type, self *component) ([]coordinate, error) {
+	aliases := make([]coordinate, 0, len(as))
+	for _, type_ := range as {
+		if !coord.type_.AssignableTo(type_) {
+			return nil, fmt.Errorf("%w: %s to %s: %s", ErrNotAssignable, coord.type_, type_, debug.Stack())
+		}
+
+		alias := coordinate{type_: type_, name: coord.name}
+		if comp, ok := c.components[alias]; (ok && comp != self) || alias == coord || slices.Contains(aliases, alias) {
+			return nil, fmt.Errorf("%w: %s: %s", ErrComponentSet, alias, debug.Stack())
+		}
+
+		aliases = append(aliases, alias)
 	}
 
-	return nil
+	return aliases, nil
+}
+
+// mergeStageDef merges stage definition with definition set for other components
+func mergeStageDef(defs map[string]stageDef, name string, def stageDef) (stageDef, error) {
+	set, ok := defs[name]
+	if !ok {
+		return def, nil
+	}
+
+	if set.mode != def.mode {
+		return stageDef{}, fmt.Errorf("%w: stage %q is %s but %s set", ErrStageConflict, name, set.mode, def.mode)
+	}
+
+	if def.rollback == "" {
+		def.rollback = set.rollback
+	}
+
+	if set.rollback != "" && set.rollback != def.rollback {
+		return stageDef{}, fmt.Errorf("%w: stage %q rollback is %q but %q set", ErrStageConflict, name, set.rollback, def.rollback)
+	}
+
+	return def, nil
 }
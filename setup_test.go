@@ -1,6 +1,7 @@
 package di
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -9,6 +10,10 @@ import (
 type setupTestType struct{}
 type setupTestType2 struct{}
 
+type setupTestInterface interface{ setup() }
+
+func (*setupTestType) setup() {}
+
 func Test_Setup(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -274,6 +279,113 @@ func Test_Setup(t *testing.T) {
 			wantSetupErr:    ErrInitialized,
 			wantErrContains: []string{"di/setup_test.go"},
 		},
+		{
+			name: "error not assignable",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[setupTestType](c,
+					Init(func(c *Container) setupTestType { return setupTestType{} }),
+					As[setupTestInterface](),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return c, nil
+			},
+			wantSetupErr:    ErrNotAssignable,
+			wantErrContains: []string{"di/setup_test.go"},
+		},
+		{
+			name: "error as component set",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[setupTestInterface](c,
+					Init(func(c *Container) setupTestInterface { return &setupTestType{} }),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*setupTestType](c,
+					Init(func(c *Container) *setupTestType { return &setupTestType{} }),
+					As[setupTestInterface](),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return c, nil
+			},
+			wantSetupErr:    ErrComponentSet,
+			wantErrContains: []string{"(di.setupTestInterface, (Unnamed))", "di/setup_test.go"},
+		},
+		{
+			name: "error as set twice",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*setupTestType](c,
+					Init(func(c *Container) *setupTestType { return &setupTestType{} }),
+					As[setupTestInterface](),
+					As[setupTestInterface](),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return c, nil
+			},
+			wantSetupErr:    ErrComponentSet,
+			wantErrContains: []string{"di/setup_test.go"},
+		},
+		{
+			name: "error scope set",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*setupTestType](c,
+					Transient(),
+					Transient(),
+					Init(func(c *Container) *setupTestType { return new(setupTestType) }),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return c, nil
+			},
+			wantSetupErr:    ErrScopeSet,
+			wantErrContains: []string{"di/setup_test.go"},
+		},
+		{
+			name: "error transient stage",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*setupTestType](c,
+					Transient(),
+					Init(func(c *Container) *setupTestType { return new(setupTestType) }),
+					OnStop(func(ctx context.Context, s *setupTestType) error { return nil }),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return c, nil
+			},
+			wantSetupErr:    ErrScopeStage,
+			wantErrContains: []string{"for transient type (*di.setupTestType)", "di/setup_test.go"},
+		},
+		{
+			name: "error scoped stage",
+			setup: func() (*Container, error) {
+				c := NewContainer()
+				err := Setup[*setupTestType](c,
+					Scoped(),
+					Init(func(c *Container) *setupTestType { return new(setupTestType) }),
+					OnStart(func(ctx context.Context, s *setupTestType) error { return nil }),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return c, nil
+			},
+			wantSetupErr:    ErrScopeStage,
+			wantErrContains: []string{"for scoped type (*di.setupTestType)", "di/setup_test.go"},
+		},
 		{
 			name: "ok 1",
 			setup: func() (*Container, error) {
@@ -316,3 +428,33 @@ func Test_Setup(t *testing.T) {
 		})
 	}
 }
+
+func Test_Value(t *testing.T) {
+	var (
+		c       = NewContainer()
+		v       = &setupTestType{}
+		stopped *setupTestType
+	)
+
+	err := Value(c, v,
+		Name("A"),
+		Stage("stop", func(ctx context.Context, v *setupTestType) error {
+			stopped = v
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	err = Value(c, v, Name("A"))
+	require.ErrorIs(t, err, ErrComponentSet)
+
+	err = Value(c, v, Init(func(c *Container) *setupTestType { return v }))
+	require.ErrorIs(t, err, ErrInitSet)
+
+	require.NoError(t, c.Init())
+	require.Same(t, v, Get[*setupTestType](c, Name("A")))
+	require.Len(t, c.Graph().Nodes, 1)
+
+	require.NoError(t, c.ExecStage(context.Background(), "stop"))
+	require.Same(t, v, stopped)
+}
@@ -2,20 +2,45 @@ package di
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type stageMode int
 
-	"golang.org/x/sync/errgroup"
+const (
+	// stage functions executed in parallel
+	stageParallel stageMode = iota
+	// stage functions executed one by one in components initialization order
+	stageOrdered
+	// stage functions executed one by one in reverse components initialization order
+	stageReverse
 )
 
-func stageFn[T any](c *Container, coord coordinate, fn func(context.Context, T) error) func(ctx context.Context) error {
-	return func(ctx context.Context) error {
-		comp, ok := c.components[coord]
-		if !ok {
-			// no way to delete component. impossible case
-			return nil
-		}
+// stageDef is stage settings shared by all the functions of stage
+type stageDef struct {
+	mode stageMode
+	// rollback is stage executed when stage failed
+	rollback string
+}
 
-		t, ok := comp.val.(T)
+func (m stageMode) String() string {
+	switch m {
+	case stageOrdered:
+		return "ordered"
+	case stageReverse:
+		return "reverse"
+	default:
+		return "parallel"
+	}
+}
+
+func stageFn[T any](fn func(context.Context, T) error) func(context.Context, any) error {
+	return func(ctx context.Context, val any) error {
+		t, ok := val.(T)
 		if !ok {
 			// no way have type other then T for coord. impossible case
 			return nil
@@ -26,6 +51,10 @@ func stageFn[T any](c *Container, coord coordinate, fn func(context.Context, T)
 }
 
 func (c *Container) checkExecStage() error {
+	if err := c.checkNotScope(); err != nil {
+		return err
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -40,28 +69,216 @@ func (c *Container) checkExecStage() error {
 	return nil
 }
 
-func Stage[T any](name string, fn func(context.Context, T) error) withStage[T] {
-	return withStage[T]{name: name, fn: fn}
+type stageOpt interface {
+	stageOpt()
+}
+
+type execStageOpt interface {
+	execStageOpt()
+}
+
+type withRollback string
+type withTimeout time.Duration
+
+func (withRollback) stageOpt()    {}
+func (withTimeout) stageOpt()     {}
+func (withTimeout) execStageOpt() {}
+
+// Rollback defines stage executed for components stage function succeeded
+// when stage function of some other component failed
+func Rollback(stage string) withRollback { return withRollback(stage) }
+
+// Timeout limits stage function execution time when passed into Stage
+// or whole stage execution time when passed into ExecStage.
+// Stage function not returned in time is abandoned
+func Timeout(d time.Duration) withTimeout { return withTimeout(d) }
+
+// Stage defines function executed in parallel with other
+// functions of the same stage
+func Stage[T any](name string, fn func(context.Context, T) error, opts ...stageOpt) withStage[T] {
+	return newStage(name, fn, stageParallel, opts...)
 }
 
-func (c *Container) ExecStage(ctx context.Context, name string) error {
+// OrderedStage defines function executed after the same stage functions
+// of component dependencies. Use it to start components
+func OrderedStage[T any](name string, fn func(context.Context, T) error, opts ...stageOpt) withStage[T] {
+	return newStage(name, fn, stageOrdered, opts...)
+}
+
+// ReverseStage defines function executed before the same stage functions
+// of component dependencies. Use it to stop components
+func ReverseStage[T any](name string, fn func(context.Context, T) error, opts ...stageOpt) withStage[T] {
+	return newStage(name, fn, stageReverse, opts...)
+}
+
+func newStage[T any](name string, fn func(context.Context, T) error, mode stageMode, opts ...stageOpt) withStage[T] {
+	s := withStage[T]{name: name, fn: fn, def: stageDef{mode: mode}}
+	for _, o := range opts {
+		switch o := o.(type) {
+		case withRollback:
+			s.def.rollback = string(o)
+		case withTimeout:
+			s.timeout = time.Duration(o)
+		}
+	}
+	return s
+}
+
+// stageComponents returns initialized components participating in stage
+// in order they were initialized and stage definition
+func (c *Container) stageComponents(name string) ([]*component, stageDef) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var comps []*component
+	for _, coord := range c.resolved {
+		comp := c.components[coord]
+		if _, ok := comp.stages[name]; ok {
+			comps = append(comps, comp)
+		}
+	}
+
+	return comps, c.stages[name]
+}
+
+func (c *Container) ExecStage(ctx context.Context, name string, opts ...execStageOpt) error {
 	if err := c.checkExecStage(); err != nil {
 		return err
 	}
 
-	eg, ctx := errgroup.WithContext(ctx)
+	var (
+		comps, def = c.stageComponents(name)
+		deadline   time.Time
+		done       []*component
+		err        error
+	)
+
+	for _, o := range opts {
+		switch o := o.(type) {
+		case withTimeout:
+			deadline = time.Now().Add(time.Duration(o))
+		}
+	}
+
+	switch def.mode {
+	case stageOrdered:
+		done, err = execStageInOrder(ctx, name, comps, deadline)
+	case stageReverse:
+		slices.Reverse(comps)
+		done, err = execStageInOrder(ctx, name, comps, deadline)
+	default:
+		done, err = execStageInParallel(ctx, name, comps, deadline)
+	}
+
+	if err != nil && def.rollback != "" {
+		// rollback should be executed even if stage failed because of context cancellation
+		err = errors.Join(err, rollbackStage(context.WithoutCancel(ctx), def.rollback, done))
+	}
+
+	return err
+}
+
+// execStageInParallel executes stage functions in parallel. Context passed into
+// stage functions cancelled on first error. Returns components stage function succeeded
+// and errors of all the failed stage functions
+func execStageInParallel(ctx context.Context, name string, comps []*component, deadline time.Time) ([]*component, error) {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done []*component
+		// errors in components order
+		errs = make([]error, len(comps))
+	)
+
 	ctx, cnl := context.WithCancelCause(ctx)
 	defer cnl(nil)
-	for _, stageFn := range c.stages[name] {
-		fn := stageFn
-		eg.Go(func() (err error) {
-			if err = fn(ctx); err != nil {
-				err = fmt.Errorf("%w: %s: %w", ErrExecuteStage, name, err)
-				cnl(err)
+	for i, comp := range comps {
+		i, comp := i, comp
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := callStage(ctx, name, comp, deadline); err != nil {
+				errs[i] = newStageError(name, comp, err)
+				cnl(errs[i])
+				return
 			}
-			return err
-		})
+
+			mu.Lock()
+			done = append(done, comp)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return done, errors.Join(errs...)
+}
+
+// execStageInOrder executes stage functions one by one stopping on first error.
+// Returns components stage function succeeded
+func execStageInOrder(ctx context.Context, name string, comps []*component, deadline time.Time) ([]*component, error) {
+	for i, comp := range comps {
+		if err := callStage(ctx, name, comp, deadline); err != nil {
+			return comps[:i], newStageError(name, comp, err)
+		}
+	}
+
+	return comps, nil
+}
+
+// rollbackStage executes rollback stage functions one by one in reverse order
+// for components stage function succeeded. Continues on error
+func rollbackStage(ctx context.Context, name string, done []*component) error {
+	var errs []error
+	for i := len(done) - 1; i >= 0; i-- {
+		comp := done[i]
+
+		if _, ok := comp.stages[name]; !ok {
+			continue
+		}
+
+		if err := callStage(ctx, name, comp, time.Time{}); err != nil {
+			errs = append(errs, newStageError(name, comp, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// callStage calls component stage function. If stage function timeout
+// or stage deadline set, stage function is abandoned when time is out
+func callStage(ctx context.Context, name string, comp *component, deadline time.Time) error {
+	st := comp.stages[name]
+
+	if st.timeout > 0 {
+		if d := time.Now().Add(st.timeout); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
 	}
 
-	return eg.Wait()
+	if deadline.IsZero() {
+		return st.fn(ctx, comp.val)
+	}
+
+	// component named by StageError
+	timeoutErr := fmt.Errorf("%w", ErrStageTimeout)
+
+	ctx, cnl := context.WithDeadlineCause(ctx, deadline, timeoutErr)
+	defer cnl()
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	done := make(chan error, 1)
+	go func() { done <- st.fn(ctx, comp.val) }()
+
+	select {
+	case err := <-done:
+		if errors.Is(err, context.DeadlineExceeded) && context.Cause(ctx) == timeoutErr {
+			return timeoutErr
+		}
+		return err
+	case <-timer.C:
+		return timeoutErr
+	}
 }
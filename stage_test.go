@@ -3,7 +3,10 @@ package di
 import (
 	"context"
 	"errors"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -144,3 +147,345 @@ func Test_error_exec_stage_before_init(t *testing.T) {
 	err := c.ExecStage(context.Background(), "stage")
 	require.ErrorIs(t, err, ErrNotInitialized)
 }
+
+type testStageDB struct{}
+type testStageServer struct{}
+
+func Test_stage_order(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(calls *[]string) (*Container, error)
+		stage string
+		want  []string
+	}{
+		{
+			name: "ordered stage executed in init order",
+			setup: func(calls *[]string) (*Container, error) {
+				c := NewContainer()
+				err := Setup[*testStageServer](c,
+					Init(func(c *Container) *testStageServer {
+						Get[*testStageDB](c)
+						return &testStageServer{}
+					}),
+					OrderedStage("start", func(ctx context.Context, s *testStageServer) error {
+						*calls = append(*calls, "start server")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*testStageDB](c,
+					Init(func(c *Container) *testStageDB { return &testStageDB{} }),
+					OrderedStage("start", func(ctx context.Context, db *testStageDB) error {
+						*calls = append(*calls, "start db")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			stage: "start",
+			want:  []string{"start db", "start server"},
+		},
+		{
+			name: "reverse stage executed in reverse init order",
+			setup: func(calls *[]string) (*Container, error) {
+				c := NewContainer()
+				err := Setup[*testStageServer](c,
+					Init(func(c *Container) *testStageServer {
+						Get[*testStageDB](c)
+						return &testStageServer{}
+					}),
+					ReverseStage("stop", func(ctx context.Context, s *testStageServer) error {
+						*calls = append(*calls, "stop server")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				err = Setup[*testStageDB](c,
+					Init(func(c *Container) *testStageDB { return &testStageDB{} }),
+					ReverseStage("stop", func(ctx context.Context, db *testStageDB) error {
+						*calls = append(*calls, "stop db")
+						return nil
+					}),
+				)
+				if err != nil {
+					return nil, err
+				}
+
+				return c, nil
+			},
+			stage: "stop",
+			want:  []string{"stop server", "stop db"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+
+			c, err := tt.setup(&calls)
+			require.NoError(t, err)
+			require.NoError(t, c.Init())
+
+			err = c.ExecStage(context.Background(), tt.stage)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, calls)
+		})
+	}
+}
+
+func Test_ordered_stage_stops_on_first_error(t *testing.T) {
+	var (
+		c      = NewContainer()
+		aError = errors.New("error in A")
+		bCalls = 0
+	)
+
+	err := Setup[testStageType](c,
+		Name("A"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		OrderedStage("stage", func(ctx context.Context, s testStageType) error { return aError }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[testStageType](c,
+		Name("B"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		OrderedStage("stage", func(ctx context.Context, s testStageType) error {
+			bCalls++
+			return nil
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	err = c.ExecStage(context.Background(), "stage")
+	require.ErrorIs(t, err, aError)
+	require.ErrorIs(t, err, ErrExecuteStage)
+	require.Equal(t, 0, bCalls)
+}
+
+func Test_error_stage_conflict(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[testStageType](c,
+		Name("A"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		OrderedStage("stage", func(ctx context.Context, s testStageType) error { return nil }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[testStageType](c,
+		Name("B"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		Stage("stage", func(ctx context.Context, s testStageType) error { return nil }),
+	)
+	require.ErrorIs(t, err, ErrStageConflict)
+	require.Contains(t, err.Error(), `stage "stage" is ordered but parallel set: for (di.testStageType, B)`)
+}
+
+func Test_error_stage_rollback_conflict(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[testStageType](c,
+		Name("A"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		Stage("stage", func(ctx context.Context, s testStageType) error { return nil }, Rollback("rollback")),
+	)
+	require.NoError(t, err)
+
+	err = Setup[testStageType](c,
+		Name("B"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		Stage("stage", func(ctx context.Context, s testStageType) error { return nil }, Rollback("other")),
+	)
+	require.ErrorIs(t, err, ErrStageConflict)
+}
+
+func Test_stage_rolled_back_for_succeeded_components(t *testing.T) {
+	var (
+		c          = NewContainer()
+		mu         sync.Mutex
+		rolledBack []string
+		cError     = errors.New("error in C")
+	)
+
+	for _, name := range []string{"A", "B", "C"} {
+		name := name
+		err := Setup[testStageType](c,
+			Name(name),
+			Init(func(c *Container) testStageType { return testStageType{val: name} }),
+			Stage("start", func(ctx context.Context, s testStageType) error {
+				if s.val == "C" {
+					return cError
+				}
+				return nil
+			}, Rollback("stop")),
+			Stage("stop", func(ctx context.Context, s testStageType) error {
+				mu.Lock()
+				defer mu.Unlock()
+				rolledBack = append(rolledBack, s.val)
+				return nil
+			}),
+		)
+		require.NoError(t, err)
+	}
+
+	err := c.Init()
+	require.NoError(t, err)
+
+	err = c.ExecStage(context.Background(), "start")
+	require.ErrorIs(t, err, cError)
+	require.ElementsMatch(t, []string{"A", "B"}, rolledBack)
+}
+
+func Test_ordered_stage_rolled_back_in_reverse_order(t *testing.T) {
+	var (
+		c          = NewContainer()
+		rolledBack []string
+		cError     = errors.New("error in C")
+		stopError  = errors.New("error stopping A")
+	)
+
+	for _, name := range []string{"A", "B", "C", "D"} {
+		name := name
+		err := Setup[testStageType](c,
+			Name(name),
+			Init(func(c *Container) testStageType { return testStageType{val: name} }),
+			OrderedStage("start", func(ctx context.Context, s testStageType) error {
+				if s.val == "C" {
+					return cError
+				}
+				return nil
+			}, Rollback("stop")),
+			ReverseStage("stop", func(ctx context.Context, s testStageType) error {
+				rolledBack = append(rolledBack, s.val)
+				if s.val == "A" {
+					return stopError
+				}
+				return nil
+			}),
+		)
+		require.NoError(t, err)
+	}
+
+	err := c.Init()
+	require.NoError(t, err)
+
+	err = c.ExecStage(context.Background(), "start")
+	require.ErrorIs(t, err, cError)
+	require.ErrorIs(t, err, stopError)
+	require.Equal(t, []string{"B", "A"}, rolledBack)
+}
+
+func Test_stage_function_timeout(t *testing.T) {
+	var (
+		c       = NewContainer()
+		unblock = make(chan struct{})
+	)
+	defer close(unblock)
+
+	err := Setup[testStageType](c,
+		Name("A"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		Stage("stop", func(ctx context.Context, s testStageType) error { return nil }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[testStageType](c,
+		Name("hung"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		Stage("stop", func(ctx context.Context, s testStageType) error {
+			// ignores context
+			<-unblock
+			return nil
+		}, Timeout(10*time.Millisecond)),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	err = c.ExecStage(context.Background(), "stop")
+	require.ErrorIs(t, err, ErrStageTimeout)
+	require.Contains(t, err.Error(), "(di.testStageType, hung)")
+}
+
+func Test_stage_timeout(t *testing.T) {
+	c := NewContainer()
+
+	err := Setup[testStageType](c,
+		Name("slow"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		OrderedStage("stop", func(ctx context.Context, s testStageType) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	err = c.ExecStage(context.Background(), "stop", Timeout(10*time.Millisecond))
+	require.ErrorIs(t, err, ErrStageTimeout)
+	require.Contains(t, err.Error(), "(di.testStageType, slow)")
+}
+
+func Test_stage_error_identifies_component(t *testing.T) {
+	var (
+		c      = NewContainer()
+		aError = errors.New("error in A")
+		bError = errors.New("error in B")
+	)
+
+	err := Setup[testStageType](c,
+		Name("A"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		Stage("start", func(ctx context.Context, s testStageType) error { return aError }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[*testStageType](c,
+		Name("B"),
+		Init(func(c *Container) *testStageType { return &testStageType{} }),
+		Stage("start", func(ctx context.Context, s *testStageType) error { return bError }),
+	)
+	require.NoError(t, err)
+
+	err = Setup[testStageType](c,
+		Name("C"),
+		Init(func(c *Container) testStageType { return testStageType{} }),
+		Stage("start", func(ctx context.Context, s testStageType) error { return nil }),
+	)
+	require.NoError(t, err)
+
+	err = c.Init()
+	require.NoError(t, err)
+
+	err = c.ExecStage(context.Background(), "start")
+	require.ErrorIs(t, err, ErrExecuteStage)
+	require.ErrorIs(t, err, aError)
+	require.ErrorIs(t, err, bError)
+
+	var stageErr *StageError
+	require.ErrorAs(t, err, &stageErr)
+	require.Equal(t, "start", stageErr.Stage)
+	require.Equal(t, reflect.TypeOf(testStageType{}), stageErr.Type)
+	require.Equal(t, "A", stageErr.Name)
+	require.Equal(t, aError, stageErr.Err)
+
+	require.Contains(t, err.Error(), "execute stage: start: (di.testStageType, A): error in A")
+	require.Contains(t, err.Error(), "execute stage: start: (*di.testStageType, B): error in B")
+}